@@ -1,6 +1,7 @@
 package gophercloud
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -99,6 +100,32 @@ func (e ErrUnexpectedResponseCode) GetStatusCode() int {
 	return e.Actual
 }
 
+// NeutronError parses the response body as the NeutronError envelope returned
+// by the networking service, e.g. {"NeutronError": {"type": "...", "message": "..."}},
+// and returns its type and message. ok is false if the body doesn't match.
+func (e ErrUnexpectedResponseCode) NeutronError() (errType, message string, ok bool) {
+	var s struct {
+		NeutronError *struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"NeutronError"`
+	}
+	if err := json.Unmarshal(e.Body, &s); err != nil || s.NeutronError == nil {
+		return "", "", false
+	}
+	return s.NeutronError.Type, s.NeutronError.Message, true
+}
+
+// BodyAsMap parses the response body as a JSON object, e.g. for structured
+// logging. It returns an error if the body is not a JSON object.
+func (e ErrUnexpectedResponseCode) BodyAsMap() (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(e.Body, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // StatusCodeError is a convenience interface to easily allow access to the
 // status code field of the various ErrDefault* types.
 //
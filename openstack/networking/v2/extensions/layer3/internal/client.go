@@ -0,0 +1,54 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+// Package internal holds the helpers shared by the NHN Cloud layer3 resource
+// packages, routingtables and internetgateways.
+package internal
+
+import (
+	"github.com/cloud-barista/nhncloud-sdk-go"
+)
+
+// CopyClient returns a copy of the service client with its own copy of the
+// provider client, which can be customized without affecting c.
+func CopyClient(c *gophercloud.ServiceClient) *gophercloud.ServiceClient {
+	provider := *c.ProviderClient
+	if reauth := c.ProviderClient.ReauthFunc; reauth != nil {
+		// Reauthenticate through the original provider and pick up its new
+		// token, so the shared token state stays consistent.
+		original := c.ProviderClient
+		provider.ReauthFunc = func() error {
+			if err := reauth(); err != nil {
+				return err
+			}
+			provider.CopyTokenFrom(original)
+			return nil
+		}
+	}
+
+	client := *c
+	client.ProviderClient = &provider
+	return &client
+}
+
+// WithHeaders returns a copy of the service client that also sends the given
+// headers, or the client itself if there are none.
+func WithHeaders(c *gophercloud.ServiceClient, headers map[string]string) *gophercloud.ServiceClient {
+	if len(headers) == 0 {
+		return c
+	}
+
+	client := *c
+	client.MoreHeaders = make(map[string]string, len(c.MoreHeaders)+len(headers))
+	for k, v := range c.MoreHeaders {
+		client.MoreHeaders[k] = v
+	}
+	for k, v := range headers {
+		client.MoreHeaders[k] = v
+	}
+	return &client
+}
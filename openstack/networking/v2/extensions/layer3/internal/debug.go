@@ -0,0 +1,139 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internal
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+)
+
+// Logger receives the debug output of the operations. The standard library
+// *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// DebugLog holds the debug logger of a package. The zero value has no logger
+// and is ready to use.
+type DebugLog struct {
+	mu     sync.RWMutex
+	logger Logger
+}
+
+// Set sets the logger; a nil value disables the debug mode.
+func (d *DebugLog) Set(l Logger) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.logger = l
+}
+
+// redactedHeaders are the headers whose value is never logged.
+var redactedHeaders = map[string]bool{
+	"X-Auth-Token":    true,
+	"X-Subject-Token": true,
+	"X-Service-Token": true,
+	"Authorization":   true,
+	"Set-Cookie":      true,
+}
+
+// Wrap returns a copy of the service client logging the method, URL, headers
+// and body of its requests and responses to the logger, with the
+// authentication headers redacted. It returns the client itself if there is
+// no logger, or if the client already logs: an operation built on another
+// one, possibly of another package, is logged once.
+func (d *DebugLog) Wrap(c *gophercloud.ServiceClient, operation string) *gophercloud.ServiceClient {
+	d.mu.RLock()
+	logger := d.logger
+	d.mu.RUnlock()
+	if logger == nil {
+		return c
+	}
+	if _, ok := c.ProviderClient.HTTPClient.Transport.(*debugTransport); ok {
+		return c
+	}
+
+	client := CopyClient(c)
+	next := client.ProviderClient.HTTPClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.ProviderClient.HTTPClient.Transport = &debugTransport{next: next, logger: logger, operation: operation}
+	return client
+}
+
+// debugTransport is an http.RoundTripper logging the requests it sends and
+// the responses it receives.
+type debugTransport struct {
+	next      http.RoundTripper
+	logger    Logger
+	operation string
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	t.logger.Printf("[DEBUG] %s request: %s %s\n%s\n%s", t.operation, req.Method, req.URL, formatHeaders(req.Header), body)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.logger.Printf("[DEBUG] %s error: %s", t.operation, err)
+		return resp, err
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	t.logger.Printf("[DEBUG] %s response: %d\n%s\n%s", t.operation, resp.StatusCode, formatHeaders(resp.Header), raw)
+	return resp, nil
+}
+
+// peekRequestBody returns the body of the request without consuming it.
+func peekRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(raw))
+	return raw, err
+}
+
+// formatHeaders renders the headers one per line, sorted by name, with the
+// authentication headers redacted.
+func formatHeaders(headers http.Header) string {
+	lines := make([]string, 0, len(headers))
+	for name, values := range headers {
+		value := strings.Join(values, ", ")
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			value = "***"
+		}
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,52 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internal
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+)
+
+// Endpoint holds the test endpoint override of a package. The zero value has
+// no override and is ready to use.
+type Endpoint struct {
+	mu       sync.RWMutex
+	endpoint string
+}
+
+// Set overrides the endpoint and returns a function restoring the previous
+// one. An empty endpoint removes the override.
+func (e *Endpoint) Set(endpoint string) (restore func()) {
+	if endpoint != "" && !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	e.mu.Lock()
+	previous := e.endpoint
+	e.endpoint = endpoint
+	e.mu.Unlock()
+
+	return func() {
+		e.mu.Lock()
+		e.endpoint = previous
+		e.mu.Unlock()
+	}
+}
+
+// ServiceURL is c.ServiceURL, with the parts appended to the overriding
+// endpoint instead of the resource base URL of c if there is one.
+func (e *Endpoint) ServiceURL(c *gophercloud.ServiceClient, parts ...string) string {
+	e.mu.RLock()
+	endpoint := e.endpoint
+	e.mu.RUnlock()
+	if endpoint == "" {
+		return c.ServiceURL(parts...)
+	}
+	return endpoint + strings.Join(parts, "/")
+}
@@ -0,0 +1,55 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// Hooks receives a notification around every operation, e.g. to record
+// latencies and error counts.
+type Hooks interface {
+	// OnRequestStart is called before the operation sends its request.
+	OnRequestStart(operation string)
+
+	// OnRequestEnd is called once the operation completed, with its duration
+	// and its error, if any.
+	OnRequestEnd(operation string, duration time.Duration, err error)
+}
+
+// HookSet holds the hooks of a package. The zero value has no hooks and is
+// ready to use.
+type HookSet struct {
+	mu    sync.RWMutex
+	hooks Hooks
+}
+
+// Set registers the hooks; a nil value disables them.
+func (s *HookSet) Set(h Hooks) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = h
+}
+
+// Observe reports the start of an operation to the registered hooks and
+// returns the function reporting its end.
+func (s *HookSet) Observe(operation string) func(error) {
+	s.mu.RLock()
+	h := s.hooks
+	s.mu.RUnlock()
+	if h == nil {
+		return func(error) {}
+	}
+
+	start := time.Now()
+	h.OnRequestStart(operation)
+	return func(err error) {
+		h.OnRequestEnd(operation, time.Since(start), err)
+	}
+}
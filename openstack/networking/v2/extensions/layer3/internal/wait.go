@@ -0,0 +1,40 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+)
+
+// PollInterval is the delay between two consecutive polls of WaitFor.
+const PollInterval = 1 * time.Second
+
+// WaitFor polls a predicate until it reports success, returns an error, or the
+// timeout elapses. On expiry it returns a gophercloud.ErrTimeOut whose message
+// says it was waiting for what.
+func WaitFor(timeout time.Duration, what string, predicate func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := predicate()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if time.Now().Add(PollInterval).After(deadline) {
+			err := gophercloud.ErrTimeOut{}
+			err.Info = fmt.Sprintf("Timed out after %s waiting for %s", timeout, what)
+			return err
+		}
+		time.Sleep(PollInterval)
+	}
+}
@@ -0,0 +1,32 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internetgateways
+
+import (
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
+
+// Logger receives the debug output of the Internet Gateway operations. It is
+// the same type as routingtables.Logger.
+type Logger = internal.Logger
+
+var debugLog internal.DebugLog
+
+// SetDebugLogger enables the debug mode: every request sent by an Internet
+// Gateway operation, List pagers included, and its response are written to
+// the logger, with the authentication headers redacted. A nil value, the
+// default, disables the debug mode.
+func SetDebugLogger(l Logger) {
+	debugLog.Set(l)
+}
+
+// withDebug returns the service client to use for an operation in debug mode.
+func withDebug(c *gophercloud.ServiceClient, operation string) *gophercloud.ServiceClient {
+	return debugLog.Wrap(c, operation)
+}
@@ -0,0 +1,62 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internetgateways
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+)
+
+// ErrExternalNetworkChange is the error context of an Update request that
+// moves an Internet Gateway to another external network.
+type ErrExternalNetworkChange struct {
+	gophercloud.ErrUnexpectedResponseCode
+	ID                string
+	ExternalNetworkID string
+}
+
+func (e ErrExternalNetworkChange) Error() string {
+	return fmt.Sprintf("Error while moving Internet Gateway [%s] to external network [%s]", e.ID, e.ExternalNetworkID)
+}
+
+// Error400 reports a rejected external network change as an
+// ErrExternalNetworkImmutable. Other validation errors, e.g. of the name, are
+// returned as a gophercloud.ErrDefault400.
+func (e ErrExternalNetworkChange) Error400(r gophercloud.ErrUnexpectedResponseCode) error {
+	_, message, ok := r.NeutronError()
+	message = strings.ToLower(message)
+	if !ok || (!strings.Contains(message, "external_network_id") && !strings.Contains(message, "external network")) {
+		return gophercloud.ErrDefault400{ErrUnexpectedResponseCode: r}
+	}
+	e.ErrUnexpectedResponseCode = r
+	return ErrExternalNetworkImmutable{e}
+}
+
+// ErrExternalNetworkImmutable is the error when the API refuses to change the
+// external network of an Internet Gateway.
+type ErrExternalNetworkImmutable struct {
+	ErrExternalNetworkChange
+}
+
+func (e ErrExternalNetworkImmutable) Error() string {
+	return fmt.Sprintf("The external network of Internet Gateway [%s] cannot be changed to [%s]", e.ID, e.ExternalNetworkID)
+}
+
+// ErrMultipleInternetGatewaysFound is the error when a lookup by name matches
+// more than one Internet Gateway. IDs lists the matching gateways so the
+// caller can pick one.
+type ErrMultipleInternetGatewaysFound struct {
+	gophercloud.ErrMultipleResourcesFound
+	IDs []string
+}
+
+func (e ErrMultipleInternetGatewaysFound) Error() string {
+	return fmt.Sprintf("Found %d Internet Gateways named %s: %s", e.Count, e.Name, strings.Join(e.IDs, ", "))
+}
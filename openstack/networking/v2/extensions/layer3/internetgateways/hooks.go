@@ -0,0 +1,30 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internetgateways
+
+import (
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
+
+// Hooks receives a notification around every Internet Gateway operation,
+// named after its function, such as "internetgateways.Get". It is the same
+// type as routingtables.Hooks.
+type Hooks = internal.Hooks
+
+var hooks internal.HookSet
+
+// SetHooks registers the hooks invoked by the Internet Gateway operations. A
+// nil value, the default, disables them.
+func SetHooks(h Hooks) {
+	hooks.Set(h)
+}
+
+// observe reports an operation to the registered hooks.
+func observe(operation string) func(error) {
+	return hooks.Observe(operation)
+}
@@ -0,0 +1,62 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internetgateways
+
+import (
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
+
+// RequestOption customizes a single Internet Gateway operation, e.g.
+// Get(client, id, WithHeader("X-Trace-Id", traceID)).
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the settings collected from the RequestOptions of an
+// operation.
+type requestOptions struct {
+	headers  map[string]string
+	tenantID string
+}
+
+// collect applies the RequestOptions of an operation.
+func collect(reqOpts []RequestOption) requestOptions {
+	var o requestOptions
+	for _, apply := range reqOpts {
+		apply(&o)
+	}
+	return o
+}
+
+// WithHeader sets an additional HTTP header on the request of the operation.
+// It takes precedence over the service client MoreHeaders, but never over the
+// authentication headers, which the provider client sets last.
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[key] = value
+	}
+}
+
+// WithTenant scopes an operation to another tenant, which requires an admin
+// token: List only returns the Internet Gateways of the tenant, and Create
+// creates the gateway on its behalf. Other operations ignore it.
+func WithTenant(tenantID string) RequestOption {
+	return func(o *requestOptions) {
+		o.tenantID = tenantID
+	}
+}
+
+// prepare returns the service client to use for an operation: a copy
+// carrying the headers of the request options and logging to the debug
+// logger if needed, or the client itself otherwise.
+func prepare(client *gophercloud.ServiceClient, operation string, reqOpts []RequestOption) *gophercloud.ServiceClient {
+	o := collect(reqOpts)
+	return withDebug(internal.WithHeaders(client, o.headers), operation)
+}
@@ -9,6 +9,7 @@ package internetgateways
 
 import (
 	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
 	"github.com/cloud-barista/nhncloud-sdk-go/pagination"
 )
 
@@ -30,20 +31,30 @@ type ListOpts struct {
 	RoutingTableID string `q:"routingtable_id"`
 }
 
-// List returns a Pager which allows you to iterate over Internet Gateways
-func List(client *gophercloud.ServiceClient, opts ListOpts) pagination.Pager {
+// List returns a Pager which allows you to iterate over Internet Gateways.
+// Only the WithHeader and WithTenant request options apply to it.
+func List(client *gophercloud.ServiceClient, opts ListOpts, reqOpts ...RequestOption) pagination.Pager {
+	o := collect(reqOpts)
+	if o.tenantID != "" {
+		opts.TenantID = o.tenantID
+	}
 	q, err := gophercloud.BuildQueryString(&opts)
 	if err != nil {
 		return pagination.Pager{Err: err}
 	}
 	
+	client = withDebug(internal.WithHeaders(client, o.headers), "internetgateways.List")
 	return pagination.NewPager(client, listURL(client)+q.String(), func(r pagination.PageResult) pagination.Page {
 		return InternetGatewayPage{pagination.LinkedPageBase{PageResult: r}}
 	})
 }
 
 // Get returns details about a specific Internet Gateway
-func Get(client *gophercloud.ServiceClient, id string) (r GetResult) {
+func Get(client *gophercloud.ServiceClient, id string, reqOpts ...RequestOption) (r GetResult) {
+	client = prepare(client, "internetgateways.Get", reqOpts)
+	done := observe("internetgateways.Get")
+	defer func() { done(r.Err) }()
+
 	resp, err := client.Get(getURL(client, id), &r.Body, nil)
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
@@ -56,6 +67,13 @@ type CreateOpts struct {
 	
 	// ExternalNetworkID is the ID of the external network to connect to
 	ExternalNetworkID string `json:"external_network_id" required:"true"`
+
+	// Description is an optional description of the Internet Gateway
+	Description string `json:"description,omitempty"`
+
+	// TenantID is the tenant that will own the Internet Gateway. Only
+	// administrators can create a gateway on behalf of another tenant.
+	TenantID string `json:"tenant_id,omitempty"`
 }
 
 // ToInternetGatewayCreateMap builds a request body from CreateOpts
@@ -69,20 +87,83 @@ type CreateOptsBuilder interface {
 }
 
 // Create creates a new Internet Gateway
-func Create(client *gophercloud.ServiceClient, opts CreateOptsBuilder) (r CreateResult) {
+func Create(client *gophercloud.ServiceClient, opts CreateOptsBuilder, reqOpts ...RequestOption) (r CreateResult) {
+	client = prepare(client, "internetgateways.Create", reqOpts)
+	done := observe("internetgateways.Create")
+	defer func() { done(r.Err) }()
+
 	b, err := opts.ToInternetGatewayCreateMap()
 	if err != nil {
 		r.Err = err
 		return
 	}
+	if tenantID := collect(reqOpts).tenantID; tenantID != "" {
+		if igw, ok := b["internetgateway"].(map[string]interface{}); ok {
+			igw["tenant_id"] = tenantID
+		}
+	}
 	
 	resp, err := client.Post(createURL(client), b, &r.Body, nil)
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
 }
 
+// UpdateOpts represents options for updating an Internet Gateway.
+//
+// The name can always be changed. Moving the gateway to another external
+// network is only accepted by API versions that support it; otherwise Update
+// fails with an ErrExternalNetworkImmutable.
+type UpdateOpts struct {
+	// Name is the new name of the Internet Gateway
+	Name string `json:"name,omitempty"`
+
+	// ExternalNetworkID is the ID of the external network to move the
+	// Internet Gateway to
+	ExternalNetworkID string `json:"external_network_id,omitempty"`
+}
+
+// ToInternetGatewayUpdateMap builds a request body from UpdateOpts
+func (opts UpdateOpts) ToInternetGatewayUpdateMap() (map[string]interface{}, error) {
+	return gophercloud.BuildRequestBody(opts, "internetgateway")
+}
+
+// UpdateOptsBuilder allows extensions to add additional attributes to the Update request
+type UpdateOptsBuilder interface {
+	ToInternetGatewayUpdateMap() (map[string]interface{}, error)
+}
+
+// Update modifies the attributes of an existing Internet Gateway
+func Update(client *gophercloud.ServiceClient, id string, opts UpdateOptsBuilder, reqOpts ...RequestOption) (r UpdateResult) {
+	client = prepare(client, "internetgateways.Update", reqOpts)
+	done := observe("internetgateways.Update")
+	defer func() { done(r.Err) }()
+
+	b, err := opts.ToInternetGatewayUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	putOpts := &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	}
+	if igw, ok := b["internetgateway"].(map[string]interface{}); ok {
+		if networkID, ok := igw["external_network_id"].(string); ok {
+			putOpts.ErrorContext = ErrExternalNetworkChange{ID: id, ExternalNetworkID: networkID}
+		}
+	}
+
+	resp, err := client.Put(updateURL(client, id), b, &r.Body, putOpts)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return
+}
+
 // Delete deletes an Internet Gateway
-func Delete(client *gophercloud.ServiceClient, id string) (r DeleteResult) {
+func Delete(client *gophercloud.ServiceClient, id string, reqOpts ...RequestOption) (r DeleteResult) {
+	client = prepare(client, "internetgateways.Delete", reqOpts)
+	done := observe("internetgateways.Delete")
+	defer func() { done(r.Err) }()
+
 	resp, err := client.Delete(deleteURL(client, id), &gophercloud.RequestOpts{
 		OkCodes: []int{200, 204},
 	})
@@ -9,7 +9,7 @@ package internetgateways
 
 import (
 	"encoding/json"
-	"time"
+	"fmt"
 
 	"github.com/cloud-barista/nhncloud-sdk-go"
 	"github.com/cloud-barista/nhncloud-sdk-go/pagination"
@@ -60,6 +60,9 @@ type InternetGateway struct {
 	// Name is the name of the Internet Gateway
 	Name string `json:"name"`
 
+	// Description is the description of the Internet Gateway, if any
+	Description string `json:"description"`
+
 	// ExternalNetworkID is the ID of the external network connected to this gateway
 	ExternalNetworkID string `json:"external_network_id"`
 
@@ -70,8 +73,9 @@ type InternetGateway struct {
 	// Possible values: available, unavailable, migrating, error
 	State string `json:"state"`
 
-	// CreateTime is the creation time of the Internet Gateway in UTC
-	CreateTime time.Time `json:"-"`
+	// CreateTime is the creation time of the Internet Gateway. The API reports
+	// it in KST; see gophercloud.NHNCloudTime for the accepted formats.
+	CreateTime gophercloud.NHNCloudTime `json:"create_time"`
 
 	// TenantID is the tenant ID that owns this Internet Gateway
 	TenantID string `json:"tenant_id"`
@@ -82,34 +86,54 @@ type InternetGateway struct {
 
 	// MigrateError contains error message if migration fails
 	MigrateError *string `json:"migrate_error"`
+
+	// ParseWarnings lists the fields of the response that could not be read
+	// and were left zero instead of failing the whole extraction. It is not
+	// part of the API.
+	ParseWarnings []string `json:"-"`
 }
 
-// UnmarshalJSON implements custom JSON unmarshaling for InternetGateway
-func (r *InternetGateway) UnmarshalJSON(b []byte) error {
+// UnmarshalJSON tolerates a create_time that none of the formats of
+// gophercloud.NHNCloudTime can parse: CreateTime is then left zero and the
+// problem is recorded in ParseWarnings, so that a single bad timestamp does
+// not break the listing of all gateways.
+func (igw *InternetGateway) UnmarshalJSON(b []byte) error {
 	type tmp InternetGateway
 	var s struct {
 		tmp
-		CreateTime string `json:"create_time"`
+		CreateTime json.RawMessage `json:"create_time"`
 	}
-	
-	err := json.Unmarshal(b, &s)
-	if err != nil {
+	if err := json.Unmarshal(b, &s); err != nil {
 		return err
 	}
-	
-	*r = InternetGateway(s.tmp)
-	
-	if s.CreateTime != "" {
-		t, err := time.Parse("2006-01-02 15:04:05", s.CreateTime)
-		if err != nil {
-			return err
+
+	*igw = InternetGateway(s.tmp)
+	igw.ParseWarnings = nil
+	if len(s.CreateTime) > 0 {
+		if err := json.Unmarshal(s.CreateTime, &igw.CreateTime); err != nil {
+			igw.CreateTime = gophercloud.NHNCloudTime{}
+			igw.ParseWarnings = append(igw.ParseWarnings, fmt.Sprintf("create_time: %v", err))
 		}
-		r.CreateTime = t
 	}
-	
 	return nil
 }
 
+// IsAttached reports whether the Internet Gateway is connected to a routing
+// table.
+func (igw InternetGateway) IsAttached() bool {
+	_, ok := igw.AttachedRoutingTableID()
+	return ok
+}
+
+// AttachedRoutingTableID returns the ID of the routing table the Internet
+// Gateway is connected to, and false if it is not connected to any.
+func (igw InternetGateway) AttachedRoutingTableID() (string, bool) {
+	if igw.RoutingTableID == nil || *igw.RoutingTableID == "" {
+		return "", false
+	}
+	return *igw.RoutingTableID, true
+}
+
 // InternetGatewayPage represents a single page of Internet Gateway results
 type InternetGatewayPage struct {
 	pagination.LinkedPageBase
@@ -133,13 +157,22 @@ func (r InternetGatewayPage) IsEmpty() (bool, error) {
 	return len(internetgateways) == 0, err
 }
 
-// ExtractInternetGateways extracts Internet Gateways from a List result
+// ExtractInternetGateways extracts Internet Gateways from a List result. The
+// slice is empty but never nil if the page holds no gateway.
 func ExtractInternetGateways(r pagination.Page) ([]InternetGateway, error) {
-	var s struct {
-		InternetGateways []InternetGateway `json:"internetgateways"`
+	var s []InternetGateway
+	err := ExtractInternetGatewaysInto(r, &s)
+	if err == nil && s == nil {
+		s = []InternetGateway{}
 	}
-	err := (r.(InternetGatewayPage)).ExtractInto(&s)
-	return s.InternetGateways, err
+	return s, err
+}
+
+// ExtractInternetGatewaysInto extracts the Internet Gateways of an
+// InternetGatewayPage into v, which must be a pointer to a slice of a
+// caller-defined type.
+func ExtractInternetGatewaysInto(r pagination.Page, v interface{}) error {
+	return r.(InternetGatewayPage).Result.ExtractIntoSlicePtr(v, "internetgateways")
 }
 
 // GetResult represents the result of a get operation
@@ -170,6 +203,20 @@ func (r CreateResult) Extract() (*InternetGateway, error) {
 	return s.InternetGateway, err
 }
 
+// UpdateResult represents the result of an update operation
+type UpdateResult struct {
+	gophercloud.Result
+}
+
+// Extract extracts an InternetGateway from an UpdateResult
+func (r UpdateResult) Extract() (*InternetGateway, error) {
+	var s struct {
+		InternetGateway *InternetGateway `json:"internetgateway"`
+	}
+	err := r.ExtractInto(&s)
+	return s.InternetGateway, err
+}
+
 // DeleteResult represents the result of a delete operation
 type DeleteResult struct {
 	gophercloud.ErrResult
@@ -0,0 +1,2 @@
+// internetgateways unit tests
+package testing
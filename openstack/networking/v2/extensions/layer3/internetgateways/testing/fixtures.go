@@ -0,0 +1,90 @@
+package testing
+
+// InternetGatewayID is the ID of the Internet Gateway used across the fixtures.
+const InternetGatewayID = "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"
+
+// UpdateRequest is the expected body of an Update request.
+const UpdateRequest = `
+{
+    "internetgateway": {
+        "name": "igw-renamed"
+    }
+}
+`
+
+// UpdateResponse is the response to an Update request.
+const UpdateResponse = `
+{
+    "internetgateway": {
+        "id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e",
+        "name": "igw-renamed",
+        "external_network_id": "751b8227-7b6a-4b3c-9d2e-1f0a2b3c4d5e",
+        "routingtable_id": null,
+        "state": "unavailable",
+        "create_time": "2024-02-13 10:45:57",
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "migrate_status": "none",
+        "migrate_error": null
+    }
+}
+`
+
+// GetMigrationResponseTemplate is a Get response whose state, migrate_status
+// and migrate_error are substituted by the caller.
+const GetMigrationResponseTemplate = `
+{
+    "internetgateway": {
+        "id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e",
+        "name": "igw-web",
+        "external_network_id": "751b8227-7b6a-4b3c-9d2e-1f0a2b3c4d5e",
+        "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+        "state": "%s",
+        "create_time": "2024-02-13 10:45:57",
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "migrate_status": "%s",
+        "migrate_error": %s
+    }
+}
+`
+
+// ListResponse is the response to a List request. Two gateways share the
+// name "igw-web".
+const ListResponse = `
+{
+    "internetgateways": [
+        {
+            "id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e",
+            "name": "igw-web",
+            "external_network_id": "751b8227-7b6a-4b3c-9d2e-1f0a2b3c4d5e",
+            "routingtable_id": null,
+            "state": "available",
+            "create_time": "2024-02-13 10:45:57",
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+            "migrate_status": "none",
+            "migrate_error": null
+        },
+        {
+            "id": "0d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a",
+            "name": "igw-web",
+            "external_network_id": "751b8227-7b6a-4b3c-9d2e-1f0a2b3c4d5e",
+            "routingtable_id": null,
+            "state": "available",
+            "create_time": "2024-02-14 09:12:03",
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+            "migrate_status": "none",
+            "migrate_error": null
+        },
+        {
+            "id": "8a9b0c1d-2e3f-4a5b-6c7d-8e9f0a1b2c3d",
+            "name": "igw-batch",
+            "external_network_id": "751b8227-7b6a-4b3c-9d2e-1f0a2b3c4d5e",
+            "routingtable_id": null,
+            "state": "available",
+            "create_time": "2024-02-15 16:30:41",
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+            "migrate_status": "none",
+            "migrate_error": null
+        }
+    ]
+}
+`
@@ -0,0 +1,448 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	fake "github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/common"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internetgateways"
+	th "github.com/cloud-barista/nhncloud-sdk-go/testhelper"
+)
+
+func TestUpdate(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		th.TestJSONRequest(t, r, UpdateRequest)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Openstack-Request-Id", "req-0b9e8d7c")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, UpdateResponse)
+	})
+
+	opts := internetgateways.UpdateOpts{Name: "igw-renamed"}
+	res := internetgateways.Update(fake.ServiceClient(), InternetGatewayID, opts)
+	th.AssertEquals(t, "req-0b9e8d7c", res.RequestID())
+
+	igw, err := res.Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "igw-renamed", igw.Name)
+	th.AssertEquals(t, InternetGatewayID, igw.ID)
+}
+
+func TestWaitForMigration(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	calls := 0
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		if calls == 0 {
+			fmt.Fprintf(w, GetMigrationResponseTemplate, "migrating", "binding_progress", "null")
+		} else {
+			fmt.Fprintf(w, GetMigrationResponseTemplate, "available", "none", "null")
+		}
+		calls++
+	})
+
+	err := internetgateways.WaitForMigration(fake.ServiceClient(), InternetGatewayID, 10*time.Second)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, calls)
+}
+
+func TestWaitForMigrationError(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, GetMigrationResponseTemplate, "error", "binding_error", `"failed to bind on new host"`)
+	})
+
+	err := internetgateways.WaitForMigration(fake.ServiceClient(), InternetGatewayID, 10*time.Second)
+	th.AssertErr(t, err)
+	if !strings.Contains(err.Error(), "failed to bind on new host") {
+		t.Fatalf("expected the migrate error in %q", err)
+	}
+}
+
+func TestUpdateExternalNetworkRejected(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"internetgateway": {"external_network_id": "862c9338-8c7b-4c4d-8e3f-2a1b3c4d5e6f"}}`)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"NeutronError": {"type": "HTTPBadRequest", "message": "Cannot update read-only attribute external_network_id"}}`)
+	})
+
+	opts := internetgateways.UpdateOpts{ExternalNetworkID: "862c9338-8c7b-4c4d-8e3f-2a1b3c4d5e6f"}
+	_, err := internetgateways.Update(fake.ServiceClient(), InternetGatewayID, opts).Extract()
+
+	immutable, ok := err.(internetgateways.ErrExternalNetworkImmutable)
+	if !ok {
+		t.Fatalf("expected ErrExternalNetworkImmutable, got %#v", err)
+	}
+	th.AssertEquals(t, InternetGatewayID, immutable.ID)
+	th.AssertEquals(t, http.StatusBadRequest, immutable.GetStatusCode())
+}
+
+func TestUpdateExternalNetworkOtherValidationError(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"NeutronError": {"type": "HTTPBadRequest", "message": "Invalid input for name. Reason: too long."}}`)
+	})
+
+	opts := internetgateways.UpdateOpts{Name: strings.Repeat("x", 300), ExternalNetworkID: "862c9338-8c7b-4c4d-8e3f-2a1b3c4d5e6f"}
+	_, err := internetgateways.Update(fake.ServiceClient(), InternetGatewayID, opts).Extract()
+	if _, ok := err.(gophercloud.ErrDefault400); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault400, got %#v", err)
+	}
+}
+
+func TestToInternetGatewayCreateMap(t *testing.T) {
+	opts := internetgateways.CreateOpts{
+		Name:              "igw-tenant",
+		ExternalNetworkID: "751b8227-7b3f-4d1a-9d5e-0c9f6a2d3b4e",
+	}
+	b, err := opts.ToInternetGatewayCreateMap()
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, map[string]interface{}{
+		"internetgateway": map[string]interface{}{
+			"name":                "igw-tenant",
+			"external_network_id": "751b8227-7b3f-4d1a-9d5e-0c9f6a2d3b4e",
+		},
+	}, b)
+
+	opts.Description = "egress for tenant b"
+	opts.TenantID = "3c5e7a9b1d2f4a6c8e0b2d4f6a8c0e1f"
+	b, err = opts.ToInternetGatewayCreateMap()
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, map[string]interface{}{
+		"internetgateway": map[string]interface{}{
+			"name":                "igw-tenant",
+			"external_network_id": "751b8227-7b3f-4d1a-9d5e-0c9f6a2d3b4e",
+			"description":         "egress for tenant b",
+			"tenant_id":           "3c5e7a9b1d2f4a6c8e0b2d4f6a8c0e1f",
+		},
+	}, b)
+}
+
+type hookRecorder struct {
+	events []string
+}
+
+func (h *hookRecorder) OnRequestStart(operation string) {
+	h.events = append(h.events, "start "+operation)
+}
+
+func (h *hookRecorder) OnRequestEnd(operation string, duration time.Duration, err error) {
+	h.events = append(h.events, fmt.Sprintf("end %s %t", operation, err == nil))
+}
+
+func TestHooks(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, UpdateResponse)
+		case "DELETE":
+			w.WriteHeader(http.StatusConflict)
+		}
+	})
+
+	recorder := &hookRecorder{}
+	internetgateways.SetHooks(recorder)
+	defer internetgateways.SetHooks(nil)
+
+	_, err := internetgateways.Update(fake.ServiceClient(), InternetGatewayID, internetgateways.UpdateOpts{Name: "igw-renamed"}).Extract()
+	th.AssertNoErr(t, err)
+	err = internetgateways.Delete(fake.ServiceClient(), InternetGatewayID).ExtractErr()
+	th.AssertEquals(t, true, err != nil)
+
+	th.AssertDeepEquals(t, []string{
+		"start internetgateways.Update",
+		"end internetgateways.Update true",
+		"start internetgateways.Delete",
+		"end internetgateways.Delete false",
+	}, recorder.events)
+}
+
+func TestFindByName(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+
+		// Ignore the name filter so that the client-side matching is
+		// exercised as well.
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListResponse)
+	})
+
+	all, err := internetgateways.ListAll(fake.ServiceClient(), internetgateways.ListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(all))
+
+	igw, err := internetgateways.FindByName(fake.ServiceClient(), "igw-batch")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "8a9b0c1d-2e3f-4a5b-6c7d-8e9f0a1b2c3d", igw.ID)
+
+	_, err = internetgateways.FindByName(fake.ServiceClient(), "igw-web")
+	multiple, ok := err.(internetgateways.ErrMultipleInternetGatewaysFound)
+	if !ok {
+		t.Fatalf("expected ErrMultipleInternetGatewaysFound, got %#v", err)
+	}
+	th.AssertEquals(t, 2, multiple.Count)
+	th.AssertDeepEquals(t, []string{InternetGatewayID, "0d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a"}, multiple.IDs)
+
+	_, err = internetgateways.FindByName(fake.ServiceClient(), "igw-missing")
+	if _, ok := err.(gophercloud.ErrResourceNotFound); !ok {
+		t.Fatalf("expected ErrResourceNotFound, got %#v", err)
+	}
+}
+
+func TestDeleteAndWait(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	gets := 0
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case "GET":
+			gets++
+			if gets > 1 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, GetMigrationResponseTemplate, "deleting", "none", "null")
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	})
+
+	err := internetgateways.DeleteAndWait(fake.ServiceClient(), InternetGatewayID, 10*time.Second)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, gets)
+
+	gets = 0
+	err = internetgateways.DeleteAndWait(fake.ServiceClient(), InternetGatewayID, 0)
+	if _, ok := err.(gophercloud.ErrTimeOut); !ok {
+		t.Fatalf("expected ErrTimeOut, got %#v", err)
+	}
+}
+
+func TestGetWithHeader(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Trace-Id", "trace-42")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetMigrationResponseTemplate, "available", "none", "null")
+	})
+
+	client := fake.ServiceClient()
+	_, err := internetgateways.Get(client, InternetGatewayID,
+		internetgateways.WithHeader("X-Trace-Id", "trace-42"),
+		internetgateways.WithHeader("X-Auth-Token", "forged")).Extract()
+	th.AssertNoErr(t, err)
+	if len(client.MoreHeaders) != 0 {
+		t.Fatalf("expected the client to be left untouched, got %v", client.MoreHeaders)
+	}
+}
+
+func TestExtractInternetGatewaysInto(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListResponse)
+	})
+
+	var gateways []struct {
+		ID         string `json:"id"`
+		CreateTime string `json:"create_time"`
+	}
+	allPages, err := internetgateways.List(fake.ServiceClient(), internetgateways.ListOpts{}).AllPages()
+	th.AssertNoErr(t, err)
+	th.AssertNoErr(t, internetgateways.ExtractInternetGatewaysInto(allPages, &gateways))
+	th.AssertEquals(t, 3, len(gateways))
+	th.AssertEquals(t, "2024-02-14 09:12:03", gateways[1].CreateTime)
+}
+
+func TestListOrphanedGateways(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `
+{
+    "internetgateways": [
+        {"id": "orphan", "state": "unavailable", "routingtable_id": null},
+        {"id": "attached", "state": "available", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "detaching", "state": "unavailable", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "migrating", "state": "migrating", "routingtable_id": null}
+    ]
+}`)
+	})
+
+	orphaned, err := internetgateways.ListOrphanedGateways(fake.ServiceClient(), internetgateways.ListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(orphaned))
+	th.AssertEquals(t, "orphan", orphaned[0].ID)
+}
+
+type debugRecorder struct {
+	lines []string
+}
+
+func (l *debugRecorder) Printf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestDebugLogger(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListResponse)
+	})
+
+	logger := &debugRecorder{}
+	internetgateways.SetDebugLogger(logger)
+	defer internetgateways.SetDebugLogger(nil)
+
+	all, err := internetgateways.ListAll(fake.ServiceClient(), internetgateways.ListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(all))
+
+	th.AssertEquals(t, 2, len(logger.lines))
+	if strings.Contains(logger.lines[0], fake.TokenID) || !strings.Contains(logger.lines[0], "X-Auth-Token: ***") {
+		t.Fatalf("expected the token to be redacted in %q", logger.lines[0])
+	}
+	if !strings.HasPrefix(logger.lines[1], "[DEBUG] internetgateways.List response: 200") || !strings.Contains(logger.lines[1], "igw-batch") {
+		t.Fatalf("unexpected response log %q", logger.lines[1])
+	}
+}
+
+func TestEmptyResultsAreNotNil(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/internetgateways", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"internetgateways": null}`)
+	})
+
+	all, err := internetgateways.ListAll(fake.ServiceClient(), internetgateways.ListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 0, len(all))
+	th.AssertEquals(t, true, all != nil)
+
+	filtered := internetgateways.FilterByState(nil, internetgateways.StateAvailable)
+	th.AssertEquals(t, 0, len(filtered))
+	th.AssertEquals(t, true, filtered != nil)
+}
+
+func TestWithTenant(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	tenantID := "3f2e1d0c9b8a4f7e6d5c4b3a2f1e0d9c"
+	th.Mux.HandleFunc("/v2.0/internetgateways", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			th.TestFormValues(t, r, map[string]string{"tenant_id": tenantID, "name": "igw-web"})
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"internetgateways": []}`)
+		case "POST":
+			th.TestJSONRequest(t, r, `{"internetgateway": {"name": "igw-web", "external_network_id": "751b8227-7b6a-4b3c-9d2e-1f0a2b3c4d5e", "tenant_id": "3f2e1d0c9b8a4f7e6d5c4b3a2f1e0d9c"}}`)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, GetMigrationResponseTemplate, "unavailable", "none", "null")
+		}
+	})
+
+	allPages, err := internetgateways.List(fake.ServiceClient(), internetgateways.ListOpts{Name: "igw-web"}, internetgateways.WithTenant(tenantID)).AllPages()
+	th.AssertNoErr(t, err)
+	empty, err := allPages.IsEmpty()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, empty)
+
+	opts := internetgateways.CreateOpts{Name: "igw-web", ExternalNetworkID: "751b8227-7b6a-4b3c-9d2e-1f0a2b3c4d5e"}
+	_, err = internetgateways.Create(fake.ServiceClient(), opts, internetgateways.WithTenant(tenantID)).Extract()
+	th.AssertNoErr(t, err)
+}
+
+func TestSetEndpointForTesting(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/mock/internetgateways/"+InternetGatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetMigrationResponseTemplate, "available", "none", "null")
+	})
+
+	restore := internetgateways.SetEndpointForTesting(th.Endpoint() + "mock")
+	ig, err := internetgateways.Get(fake.ServiceClient(), InternetGatewayID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, InternetGatewayID, ig.ID)
+
+	restore()
+	_, err = internetgateways.Get(fake.ServiceClient(), InternetGatewayID).Extract()
+	if _, ok := err.(gophercloud.ErrDefault404); !ok {
+		t.Fatalf("expected ErrDefault404 once restored, got %#v", err)
+	}
+}
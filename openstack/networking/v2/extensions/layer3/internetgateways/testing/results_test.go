@@ -0,0 +1,106 @@
+package testing
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internetgateways"
+	th "github.com/cloud-barista/nhncloud-sdk-go/testhelper"
+)
+
+func TestCreateTimeIsKST(t *testing.T) {
+	var igw internetgateways.InternetGateway
+	err := json.Unmarshal([]byte(`{"id": "igw", "create_time": "2024-02-13 10:45:57"}`), &igw)
+	th.AssertNoErr(t, err)
+
+	expected := time.Date(2024, 2, 13, 1, 45, 57, 0, time.UTC)
+	if !igw.CreateTime.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, igw.CreateTime.UTC())
+	}
+}
+
+func TestCreateTimeWithExplicitZone(t *testing.T) {
+	// Only zone-less timestamps are read as KST.
+	for raw, expected := range map[string]time.Time{
+		"2024-02-13 10:45:57":       time.Date(2024, 2, 13, 1, 45, 57, 0, time.UTC),
+		"2024-02-13T01:45:57+00:00": time.Date(2024, 2, 13, 1, 45, 57, 0, time.UTC),
+		"2024-02-13T10:45:57+09:00": time.Date(2024, 2, 13, 1, 45, 57, 0, time.UTC),
+	} {
+		var igw internetgateways.InternetGateway
+		err := json.Unmarshal([]byte(`{"id": "igw", "create_time": "`+raw+`"}`), &igw)
+		th.AssertNoErr(t, err)
+		if !igw.CreateTime.Equal(expected) {
+			t.Errorf("%s: expected %s, got %s", raw, expected, igw.CreateTime.UTC())
+		}
+	}
+}
+
+func TestCreateTimeWithMicroseconds(t *testing.T) {
+	var igw internetgateways.InternetGateway
+	err := json.Unmarshal([]byte(`{"id": "igw", "create_time": "2024-02-13 10:45:57.123456"}`), &igw)
+	th.AssertNoErr(t, err)
+
+	expected := time.Date(2024, 2, 13, 1, 45, 57, 123456000, time.UTC)
+	if !igw.CreateTime.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, igw.CreateTime.UTC())
+	}
+	th.AssertEquals(t, "2024-02-13 10:45:57.123456", igw.CreateTime.String())
+}
+
+func TestMalformedCreateTime(t *testing.T) {
+	var igws []internetgateways.InternetGateway
+	err := json.Unmarshal([]byte(`[
+		{"id": "igw-bad", "name": "igw-web", "state": "available", "create_time": "13/02/2024 10h45"},
+		{"id": "igw-ok", "create_time": "2024-02-13 10:45:57"}
+	]`), &igws)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(igws))
+
+	th.AssertEquals(t, "igw-web", igws[0].Name)
+	th.AssertEquals(t, "available", igws[0].State)
+	th.AssertEquals(t, true, igws[0].CreateTime.IsZero())
+	th.AssertEquals(t, 1, len(igws[0].ParseWarnings))
+
+	th.AssertEquals(t, false, igws[1].CreateTime.IsZero())
+	th.AssertEquals(t, 0, len(igws[1].ParseWarnings))
+
+	// Other malformed fields still fail the unmarshal.
+	var igw internetgateways.InternetGateway
+	if err := json.Unmarshal([]byte(`{"id": 42}`), &igw); err == nil {
+		t.Fatal("expected an error for a malformed id")
+	}
+}
+
+func TestFilterByState(t *testing.T) {
+	gateways := []internetgateways.InternetGateway{
+		{ID: "igw-ok", State: "available"},
+		{ID: "igw-broken", State: "error"},
+		{ID: "igw-moving", State: "migrating"},
+		{ID: "igw-idle", State: "unavailable"},
+	}
+
+	filtered := internetgateways.FilterByState(gateways, internetgateways.StateError, internetgateways.StateMigrating)
+	th.AssertEquals(t, 2, len(filtered))
+	th.AssertEquals(t, "igw-broken", filtered[0].ID)
+	th.AssertEquals(t, "igw-moving", filtered[1].ID)
+
+	th.AssertEquals(t, 0, len(internetgateways.FilterByState(gateways)))
+}
+
+func TestAttachedRoutingTableID(t *testing.T) {
+	var igw internetgateways.InternetGateway
+	th.AssertEquals(t, false, igw.IsAttached())
+
+	empty := ""
+	igw.RoutingTableID = &empty
+	_, ok := igw.AttachedRoutingTableID()
+	th.AssertEquals(t, false, ok)
+
+	rtID := "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"
+	igw.RoutingTableID = &rtID
+	id, ok := igw.AttachedRoutingTableID()
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, rtID, id)
+	th.AssertEquals(t, true, igw.IsAttached())
+}
@@ -1,15 +1,31 @@
 package internetgateways
 
-import "github.com/cloud-barista/nhncloud-sdk-go"
+import (
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
 
 const resourcePath = "internetgateways"
 
+var testEndpoint internal.Endpoint
+
+// SetEndpointForTesting redirects the Internet Gateway requests to endpoint,
+// like routingtables.SetEndpointForTesting. It is meant for tests only.
+func SetEndpointForTesting(endpoint string) (restore func()) {
+	return testEndpoint.Set(endpoint)
+}
+
+// serviceURL is c.ServiceURL, honoring SetEndpointForTesting.
+func serviceURL(c *gophercloud.ServiceClient, parts ...string) string {
+	return testEndpoint.ServiceURL(c, parts...)
+}
+
 func rootURL(c *gophercloud.ServiceClient) string {
-	return c.ServiceURL(resourcePath)
+	return serviceURL(c, resourcePath)
 }
 
 func resourceURL(c *gophercloud.ServiceClient, id string) string {
-	return c.ServiceURL(resourcePath, id)
+	return serviceURL(c, resourcePath, id)
 }
 
 func listURL(c *gophercloud.ServiceClient) string {
@@ -24,6 +40,10 @@ func createURL(c *gophercloud.ServiceClient) string {
 	return rootURL(c)
 }
 
+func updateURL(c *gophercloud.ServiceClient, id string) string {
+	return resourceURL(c, id)
+}
+
 func deleteURL(c *gophercloud.ServiceClient, id string) string {
 	return resourceURL(c, id)
 }
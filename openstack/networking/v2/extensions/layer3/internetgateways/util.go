@@ -0,0 +1,143 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package internetgateways
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
+
+// WaitForMigration will continually poll an Internet Gateway until its
+// maintenance migration settles, i.e. MigrateStatus is "none" and State is
+// "available". It returns an error as soon as the migration reports one of
+// the *_error statuses, and a gophercloud.ErrTimeOut if the gateway does not
+// settle within the timeout.
+func WaitForMigration(client *gophercloud.ServiceClient, id string, timeout time.Duration) error {
+	what := fmt.Sprintf("Internet Gateway [%s] to finish migrating", id)
+	return internal.WaitFor(timeout, what, func() (bool, error) {
+		current, err := Get(client, id).Extract()
+		if err != nil {
+			return false, err
+		}
+
+		switch MigrateStatus(current.MigrateStatus) {
+		case MigrateStatusUnbindingError, MigrateStatusBindingError:
+			msg := fmt.Sprintf("Internet Gateway [%s] migration failed with status [%s]", id, current.MigrateStatus)
+			if current.MigrateError != nil && *current.MigrateError != "" {
+				msg += ": " + *current.MigrateError
+			}
+			return false, errors.New(msg)
+		case MigrateStatusNone:
+			return InternetGatewayState(current.State) == StateAvailable, nil
+		}
+
+		return false, nil
+	})
+}
+
+// FilterByState returns the Internet Gateways whose state is one of the given
+// states. The result is empty but never nil if none matches.
+func FilterByState(gateways []InternetGateway, states ...InternetGatewayState) []InternetGateway {
+	filtered := make([]InternetGateway, 0)
+	for _, igw := range gateways {
+		for _, state := range states {
+			if InternetGatewayState(igw.State) == state {
+				filtered = append(filtered, igw)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// ListAll lists Internet Gateways, following all pages, and returns them as a
+// single slice.
+func ListAll(client *gophercloud.ServiceClient, opts ListOpts) ([]InternetGateway, error) {
+	allPages, err := List(client, opts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	return ExtractInternetGateways(allPages)
+}
+
+// FindByName returns the Internet Gateway with the given name. It returns a
+// gophercloud.ErrResourceNotFound if there is none, and an
+// ErrMultipleInternetGatewaysFound if the name is ambiguous.
+func FindByName(client *gophercloud.ServiceClient, name string) (*InternetGateway, error) {
+	allGateways, err := ListAll(client, ListOpts{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []InternetGateway
+	for _, igw := range allGateways {
+		if igw.Name == name {
+			matches = append(matches, igw)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		err := gophercloud.ErrResourceNotFound{}
+		err.ResourceType = "Internet Gateway"
+		err.Name = name
+		return nil, err
+	case 1:
+		return &matches[0], nil
+	default:
+		err := ErrMultipleInternetGatewaysFound{}
+		err.ResourceType = "Internet Gateway"
+		err.Name = name
+		err.Count = len(matches)
+		for _, igw := range matches {
+			err.IDs = append(err.IDs, igw.ID)
+		}
+		return nil, err
+	}
+}
+
+// DeleteAndWait deletes an Internet Gateway and then polls it until the API
+// no longer knows it, so that the gateway is actually gone rather than merely
+// scheduled for deletion when it returns. It returns a gophercloud.ErrTimeOut
+// if the gateway is still there after the timeout.
+func DeleteAndWait(client *gophercloud.ServiceClient, id string, timeout time.Duration) error {
+	if err := Delete(client, id).ExtractErr(); err != nil {
+		return err
+	}
+
+	what := fmt.Sprintf("Internet Gateway [%s] to be deleted", id)
+	return internal.WaitFor(timeout, what, func() (bool, error) {
+		err := Get(client, id).Err
+		if _, ok := err.(gophercloud.ErrDefault404); ok {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// ListOrphanedGateways lists the Internet Gateways that are not attached to
+// any routing table and are in the "unavailable" state, i.e. those that only
+// incur costs and can be deleted.
+func ListOrphanedGateways(client *gophercloud.ServiceClient, opts ListOpts) ([]InternetGateway, error) {
+	allGateways, err := ListAll(client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	orphaned := make([]InternetGateway, 0)
+	for _, igw := range FilterByState(allGateways, StateUnavailable) {
+		if !igw.IsAttached() {
+			orphaned = append(orphaned, igw)
+		}
+	}
+	return orphaned, nil
+}
@@ -0,0 +1,34 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package routingtables
+
+import (
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
+
+// Logger receives the debug output of the operations of this package. The
+// standard library *log.Logger satisfies it. It is shared with
+// internetgateways, so a single logger can be registered with both packages.
+type Logger = internal.Logger
+
+var debugLog internal.DebugLog
+
+// SetDebugLogger enables the debug mode: the method, URL, headers and body of
+// every request sent by an operation of this package, List pagers included,
+// and of its response are written to the logger. Authentication headers are
+// redacted. A nil value, the default, disables the debug mode.
+func SetDebugLogger(l Logger) {
+	debugLog.Set(l)
+}
+
+// withDebug returns a copy of the service client logging its requests to the
+// debug logger, or the client itself if the debug mode is disabled.
+func withDebug(c *gophercloud.ServiceClient, operation string) *gophercloud.ServiceClient {
+	return debugLog.Wrap(c, operation)
+}
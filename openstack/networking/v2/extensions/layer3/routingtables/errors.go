@@ -0,0 +1,520 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package routingtables
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+)
+
+// ErrRoutingTable is a generic error type for routing table HTTP operations.
+type ErrRoutingTable struct {
+	gophercloud.ErrUnexpectedResponseCode
+	ID string
+}
+
+func (e ErrRoutingTable) Error() string {
+	return fmt.Sprintf("Error while executing HTTP request for routing table [%s]", e.ID)
+}
+
+// Error404 overrides the generic 404 error message.
+func (e ErrRoutingTable) Error404(r gophercloud.ErrUnexpectedResponseCode) error {
+	e.ErrUnexpectedResponseCode = r
+	return ErrRoutingTableNotFound{e}
+}
+
+// ErrRoutingTableNotFound is the error when a 404 is received while
+// retrieving a routing table. ID holds the requested routing table ID.
+type ErrRoutingTableNotFound struct {
+	ErrRoutingTable
+}
+
+func (e ErrRoutingTableNotFound) Error() string {
+	return fmt.Sprintf("Unable to find routing table [%s]", e.ID)
+}
+
+// ErrMultipleRoutingTablesFound is the error when a lookup by name matches
+// more than one routing table. IDs lists the matching routing tables so the
+// caller can pick one.
+type ErrMultipleRoutingTablesFound struct {
+	gophercloud.ErrMultipleResourcesFound
+	IDs []string
+}
+
+func (e ErrMultipleRoutingTablesFound) Error() string {
+	return fmt.Sprintf("Found %d routing tables named %s: %s", e.Count, e.Name, strings.Join(e.IDs, ", "))
+}
+
+// ErrDefaultRoute is the error when attempting to delete a system-managed
+// route, which NHN Cloud does not allow.
+type ErrDefaultRoute struct {
+	gophercloud.BaseError
+	RouteID string
+	CIDR    string
+}
+
+func (e ErrDefaultRoute) Error() string {
+	return fmt.Sprintf("Route [%s] to %s is a system default route and cannot be deleted", e.RouteID, e.CIDR)
+}
+
+// ErrDetachSubnets is the error returned by DetachAllSubnets when one or more
+// subnets could not be disassociated. Failures maps each failed subnet ID to
+// the error returned for it.
+type ErrDetachSubnets struct {
+	gophercloud.BaseError
+	RoutingTableID string
+	SubnetIDs      []string
+	Failures       map[string]error
+}
+
+func (e ErrDetachSubnets) Error() string {
+	msgs := make([]string, 0, len(e.SubnetIDs))
+	for _, id := range e.SubnetIDs {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", id, e.Failures[id]))
+	}
+	return fmt.Sprintf("Failed to detach %d subnet(s) from routing table [%s]: %s",
+		len(e.SubnetIDs), e.RoutingTableID, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual detach errors, in the order the subnets were
+// processed.
+func (e ErrDetachSubnets) Unwrap() []error {
+	errs := make([]error, 0, len(e.SubnetIDs))
+	for _, id := range e.SubnetIDs {
+		errs = append(errs, e.Failures[id])
+	}
+	return errs
+}
+
+// ErrRoutingTableNotDefault is the error when SetAsDefault completes but the
+// routing table is still not reported as the default one of its VPC.
+type ErrRoutingTableNotDefault struct {
+	gophercloud.BaseError
+	ID string
+}
+
+func (e ErrRoutingTableNotDefault) Error() string {
+	return fmt.Sprintf("Routing table [%s] was set as default but is not reported as the default table", e.ID)
+}
+
+// ErrMissingRoutingTable is the error when a successful response to an
+// operation on a routing table does not hold the routing table.
+type ErrMissingRoutingTable struct {
+	gophercloud.BaseError
+	ID string
+}
+
+func (e ErrMissingRoutingTable) Error() string {
+	return fmt.Sprintf("The response for routing table [%s] does not hold a routing table", e.ID)
+}
+
+// ErrCreateRoutes is the error returned by CreateRoutesConcurrently when one
+// or more routes could not be created. Failed holds the options of the failed
+// routes and Errors the matching errors, both in input order.
+type ErrCreateRoutes struct {
+	gophercloud.BaseError
+	Failed []CreateRouteOpts
+	Errors []error
+}
+
+func (e ErrCreateRoutes) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for i, opts := range e.Failed {
+		msgs = append(msgs, fmt.Sprintf("%s via %s: %s", opts.CIDR, opts.Gateway, e.Errors[i]))
+	}
+	return fmt.Sprintf("Failed to create %d route(s): %s", len(e.Failed), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual create errors.
+func (e ErrCreateRoutes) Unwrap() []error {
+	return e.Errors
+}
+
+// ErrRoutingTableCreate is the error context of a Create request. Name holds
+// the name of the routing table being created.
+type ErrRoutingTableCreate struct {
+	gophercloud.ErrUnexpectedResponseCode
+	Name string
+}
+
+func (e ErrRoutingTableCreate) Error() string {
+	return fmt.Sprintf("Error while creating routing table %s", e.Name)
+}
+
+// Error403 reports a quota rejection as an ErrQuotaExceeded.
+func (e ErrRoutingTableCreate) Error403(r gophercloud.ErrUnexpectedResponseCode) error {
+	if quotaErr, ok := quotaExceeded(r); ok {
+		return quotaErr
+	}
+	return gophercloud.ErrDefault403{ErrUnexpectedResponseCode: r}
+}
+
+// Error409 reports a quota rejection as an ErrQuotaExceeded.
+func (e ErrRoutingTableCreate) Error409(r gophercloud.ErrUnexpectedResponseCode) error {
+	if quotaErr, ok := quotaExceeded(r); ok {
+		return quotaErr
+	}
+	return gophercloud.ErrDefault409{ErrUnexpectedResponseCode: r}
+}
+
+// ErrQuotaExceeded is the error when a request is refused because the tenant
+// has reached its quota. Resource holds the kind of resource reported by the
+// API, e.g. "routingtable".
+type ErrQuotaExceeded struct {
+	gophercloud.ErrUnexpectedResponseCode
+	Resource string
+	Message  string
+}
+
+func (e ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("Quota exceeded for resource %s: %s", e.Resource, e.Message)
+}
+
+// quotaResourcePattern extracts the resource kind from a Neutron quota error
+// message such as "Quota exceeded for resources: ['routingtable']."
+var quotaResourcePattern = regexp.MustCompile(`\['([^']+)'`)
+
+// quotaExceeded inspects an error response and returns an ErrQuotaExceeded if
+// it describes a quota rejection.
+func quotaExceeded(r gophercloud.ErrUnexpectedResponseCode) (ErrQuotaExceeded, bool) {
+	errType, message, ok := r.NeutronError()
+	if !ok {
+		return ErrQuotaExceeded{}, false
+	}
+	if errType != "OverQuota" && !strings.Contains(strings.ToLower(message), "quota") {
+		return ErrQuotaExceeded{}, false
+	}
+
+	resource := "routingtable"
+	if m := quotaResourcePattern.FindStringSubmatch(message); m != nil {
+		resource = m[1]
+	}
+	return ErrQuotaExceeded{ErrUnexpectedResponseCode: r, Resource: resource, Message: message}, true
+}
+
+// ErrGatewayStillAttached is the error when a detach request completes but
+// the routing table still references an internet gateway.
+type ErrGatewayStillAttached struct {
+	gophercloud.BaseError
+	ID        string
+	GatewayID string
+}
+
+func (e ErrGatewayStillAttached) Error() string {
+	return fmt.Sprintf("Internet gateway [%s] is still attached to routing table [%s] after detach", e.GatewayID, e.ID)
+}
+
+// ErrEndpointUnreachable is the error returned by Ping when the networking
+// endpoint could not be reached at all, as opposed to answering with an HTTP
+// error. Err holds the underlying transport error.
+type ErrEndpointUnreachable struct {
+	gophercloud.BaseError
+	URL string
+	Err error
+}
+
+func (e ErrEndpointUnreachable) Error() string {
+	return fmt.Sprintf("Unable to reach networking endpoint %s: %s", e.URL, e.Err)
+}
+
+// Unwrap returns the underlying transport error.
+func (e ErrEndpointUnreachable) Unwrap() error {
+	return e.Err
+}
+
+// ErrIPv6Route is the error context of a CreateRoute request for an IPv6
+// destination.
+type ErrIPv6Route struct {
+	gophercloud.ErrUnexpectedResponseCode
+	CIDR string
+}
+
+func (e ErrIPv6Route) Error() string {
+	return fmt.Sprintf("Error while creating IPv6 route to %s", e.CIDR)
+}
+
+// Error400 reports a rejected IPv6 route as an ErrIPv6Unsupported.
+func (e ErrIPv6Route) Error400(r gophercloud.ErrUnexpectedResponseCode) error {
+	e.ErrUnexpectedResponseCode = r
+	return ErrIPv6Unsupported{e}
+}
+
+// ErrIPv6Unsupported is the error when the API rejects an IPv6 route, which
+// happens in regions or VPCs without IPv6 support.
+type ErrIPv6Unsupported struct {
+	ErrIPv6Route
+}
+
+func (e ErrIPv6Unsupported) Error() string {
+	msg := fmt.Sprintf("IPv6 route to %s was rejected; IPv6 routing may not be supported in this region or VPC", e.CIDR)
+	if _, message, ok := e.NeutronError(); ok {
+		msg += ": " + message
+	}
+	return msg
+}
+
+// ErrRouteNotRecovered is the error when a route creation succeeded with an
+// empty response body and the created route could not be identified. Matches
+// is the number of candidate routes found, and Err the error that prevented
+// listing them, if any.
+type ErrRouteNotRecovered struct {
+	gophercloud.BaseError
+	RoutingTableID string
+	CIDR           string
+	Gateway        string
+	Matches        int
+	Err            error
+}
+
+func (e ErrRouteNotRecovered) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("Unable to identify the route to %s via %s created in routing table [%s]: %s", e.CIDR, e.Gateway, e.RoutingTableID, e.Err)
+	}
+	return fmt.Sprintf("Unable to identify the route to %s via %s created in routing table [%s]: %d routes match", e.CIDR, e.Gateway, e.RoutingTableID, e.Matches)
+}
+
+func (e ErrRouteNotRecovered) Unwrap() error {
+	return e.Err
+}
+
+// ErrMalformedPageLinks is the error when the pagination links of a list
+// response cannot be followed. Key is the body key holding the links and Href
+// the offending link, when known.
+type ErrMalformedPageLinks struct {
+	gophercloud.BaseError
+	Key    string
+	Href   string
+	Reason string
+	Err    error
+}
+
+func (e ErrMalformedPageLinks) Error() string {
+	msg := "Malformed pagination links"
+	if e.Key != "" {
+		msg += fmt.Sprintf(" in %q", e.Key)
+	}
+	if e.Href != "" {
+		msg += fmt.Sprintf(" (%s)", e.Href)
+	}
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e ErrMalformedPageLinks) Unwrap() error {
+	return e.Err
+}
+
+// ErrRollbackFailed is the error when a workflow failed with Err and the
+// routing table it created could not be deleted afterwards. The routing table
+// identified by RoutingTableID is left behind and must be cleaned up.
+type ErrRollbackFailed struct {
+	gophercloud.BaseError
+	RoutingTableID string
+	Err            error
+	RollbackErr    error
+}
+
+func (e ErrRollbackFailed) Error() string {
+	return fmt.Sprintf("%s; deleting routing table [%s] failed as well: %s", e.Err, e.RoutingTableID, e.RollbackErr)
+}
+
+func (e ErrRollbackFailed) Unwrap() []error {
+	return []error{e.Err, e.RollbackErr}
+}
+
+// ErrDistributedUnchanged is the error when an Update requesting a routing
+// type change succeeded but the routing table kept its routing type. Reasons
+// lists what likely prevented the change, and is empty if nothing explains it.
+type ErrDistributedUnchanged struct {
+	gophercloud.BaseError
+	ID        string
+	Requested bool
+	Reasons   []string
+}
+
+func (e ErrDistributedUnchanged) Error() string {
+	routingType := "centralized"
+	if e.Requested {
+		routingType = "distributed"
+	}
+	msg := fmt.Sprintf("Routing table [%s] was not made %s", e.ID, routingType)
+	if len(e.Reasons) == 0 {
+		return msg + ": the API ignored the change"
+	}
+	return msg + ": " + strings.Join(e.Reasons, ", ")
+}
+
+// ErrRouteDuplicated is the error when MoveRoute created the route with ID
+// CopyID in the target routing table, but could neither delete the original
+// route nor the copy. Both routes exist and one of them must be cleaned up.
+type ErrRouteDuplicated struct {
+	gophercloud.BaseError
+	RouteID     string
+	CopyID      string
+	Err         error
+	RollbackErr error
+}
+
+func (e ErrRouteDuplicated) Error() string {
+	return fmt.Sprintf("Route [%s] was copied to [%s] but could not be deleted: %s; deleting the copy failed as well: %s", e.RouteID, e.CopyID, e.Err, e.RollbackErr)
+}
+
+func (e ErrRouteDuplicated) Unwrap() []error {
+	return []error{e.Err, e.RollbackErr}
+}
+
+// ErrGatewayNotFound is the error when AttachGateway was asked to verify the
+// internet gateway first and the gateway does not exist.
+type ErrGatewayNotFound struct {
+	gophercloud.BaseError
+	RoutingTableID string
+	GatewayID      string
+}
+
+func (e ErrGatewayNotFound) Error() string {
+	return fmt.Sprintf("Internet gateway [%s] not found; it cannot be attached to routing table [%s]", e.GatewayID, e.RoutingTableID)
+}
+
+// ErrGatewayAlreadyAttached is the error when AttachGateway is refused because
+// the internet gateway is already attached to another routing table, given by
+// AttachedRoutingTableID.
+type ErrGatewayAlreadyAttached struct {
+	gophercloud.ErrUnexpectedResponseCode
+	RoutingTableID         string
+	GatewayID              string
+	AttachedRoutingTableID string
+}
+
+func (e ErrGatewayAlreadyAttached) Error() string {
+	return fmt.Sprintf("Internet gateway [%s] is already attached to routing table [%s]; it cannot be attached to routing table [%s]", e.GatewayID, e.AttachedRoutingTableID, e.RoutingTableID)
+}
+
+// ErrRouteNotInTable is the error when a route looked up through
+// GetRouteInTable belongs to another routing table than the expected one.
+type ErrRouteNotInTable struct {
+	gophercloud.BaseError
+	RouteID              string
+	RoutingTableID       string
+	ActualRoutingTableID string
+}
+
+func (e ErrRouteNotInTable) Error() string {
+	return fmt.Sprintf("Route [%s] belongs to routing table [%s], not [%s]", e.RouteID, e.ActualRoutingTableID, e.RoutingTableID)
+}
+
+// ErrUpdateConflict is the error when UpdateRoutingTableCAS gave up because
+// the routing table kept being modified concurrently.
+type ErrUpdateConflict struct {
+	gophercloud.BaseError
+	ID       string
+	Attempts int
+}
+
+func (e ErrUpdateConflict) Error() string {
+	return fmt.Sprintf("Routing table [%s] was modified concurrently on each of %d update attempts", e.ID, e.Attempts)
+}
+
+// ErrGatewayDetach is the error context of the requests detaching the
+// internet gateway of a routing table.
+type ErrGatewayDetach struct {
+	gophercloud.ErrUnexpectedResponseCode
+	RoutingTableID string
+}
+
+func (e ErrGatewayDetach) Error() string {
+	return fmt.Sprintf("Error while detaching the internet gateway of routing table [%s]", e.RoutingTableID)
+}
+
+// Error400 reports the refusal to detach the gateway of a default routing
+// table as an ErrCannotDetachDefault.
+func (e ErrGatewayDetach) Error400(r gophercloud.ErrUnexpectedResponseCode) error {
+	if err, ok := cannotDetachDefault(r, e.RoutingTableID); ok {
+		return err
+	}
+	return gophercloud.ErrDefault400{ErrUnexpectedResponseCode: r}
+}
+
+// Error409 reports the refusal to detach the gateway of a default routing
+// table as an ErrCannotDetachDefault.
+func (e ErrGatewayDetach) Error409(r gophercloud.ErrUnexpectedResponseCode) error {
+	if err, ok := cannotDetachDefault(r, e.RoutingTableID); ok {
+		return err
+	}
+	return gophercloud.ErrDefault409{ErrUnexpectedResponseCode: r}
+}
+
+// cannotDetachDefaultPattern matches the Neutron error message refusing to
+// detach the gateway of a default routing table, such as "Cannot detach
+// gateway from default routing table".
+var cannotDetachDefaultPattern = regexp.MustCompile(`(?i)\bdetach\b.*\bdefault[ _]?routing[ _]?table\b`)
+
+// cannotDetachDefault inspects an error response and returns an
+// ErrCannotDetachDefault if the API refused the detach because the routing
+// table is the default one.
+func cannotDetachDefault(r gophercloud.ErrUnexpectedResponseCode, routingtableID string) (ErrCannotDetachDefault, bool) {
+	_, message, ok := r.NeutronError()
+	if !ok || !cannotDetachDefaultPattern.MatchString(message) {
+		return ErrCannotDetachDefault{}, false
+	}
+	return ErrCannotDetachDefault{ErrUnexpectedResponseCode: r, RoutingTableID: routingtableID, Message: message}, true
+}
+
+// ErrCannotDetachDefault is the error when the internet gateway of the
+// default routing table of a VPC cannot be detached. Message holds the
+// message of the API, and is empty if the detach was refused client-side by
+// WithDefaultTableCheck.
+type ErrCannotDetachDefault struct {
+	gophercloud.ErrUnexpectedResponseCode
+	RoutingTableID string
+	Message        string
+}
+
+func (e ErrCannotDetachDefault) Error() string {
+	msg := fmt.Sprintf("Routing table [%s] is the default routing table of its VPC; its internet gateway cannot be detached", e.RoutingTableID)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// ErrResetRoutes is the error returned by ResetRoutes when one or more routes
+// could not be deleted. Failures maps each failed route ID to the error
+// returned for it.
+type ErrResetRoutes struct {
+	gophercloud.BaseError
+	RoutingTableID string
+	RouteIDs       []string
+	Failures       map[string]error
+}
+
+func (e ErrResetRoutes) Error() string {
+	msgs := make([]string, 0, len(e.RouteIDs))
+	for _, id := range e.RouteIDs {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", id, e.Failures[id]))
+	}
+	return fmt.Sprintf("Failed to delete %d route(s) of routing table [%s]: %s",
+		len(e.RouteIDs), e.RoutingTableID, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual delete errors, in the order the routes were
+// processed.
+func (e ErrResetRoutes) Unwrap() []error {
+	errs := make([]error, 0, len(e.RouteIDs))
+	for _, id := range e.RouteIDs {
+		errs = append(errs, e.Failures[id])
+	}
+	return errs
+}
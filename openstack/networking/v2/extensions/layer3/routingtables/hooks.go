@@ -0,0 +1,35 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package routingtables
+
+import (
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
+
+// Hooks receives a notification around every routing table and route
+// operation, e.g. to record latencies and error counts. Operations are named
+// after their function, such as "routingtables.Get"; operations built on
+// others, like SetAsDefault, also report the operations they perform.
+// Listings through a Pager are not reported. It is shared with
+// internetgateways, so a single implementation can be registered with both
+// packages.
+type Hooks = internal.Hooks
+
+var hooks internal.HookSet
+
+// SetHooks registers the hooks invoked by the operations of this package. A
+// nil value, the default, disables them. Hooks may be called concurrently.
+func SetHooks(h Hooks) {
+	hooks.Set(h)
+}
+
+// observe reports the start of an operation to the registered hooks and
+// returns the function reporting its end.
+func observe(operation string) func(error) {
+	return hooks.Observe(operation)
+}
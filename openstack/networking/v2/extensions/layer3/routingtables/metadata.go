@@ -0,0 +1,115 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package routingtables
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+)
+
+// The routing table API has neither a description nor tags, so metadata such
+// as the owner of a routing table is kept in its name, following the
+// convention implemented by EncodeNameMetadata and DecodeNameMetadata:
+//
+//	web-rt{owner=team-a,env=prod}
+//
+// The base name comes first so that the routing table stays recognizable in
+// the console, followed by the key=value pairs sorted by key between braces.
+
+// MaxNameLength is the maximum length of a routing table name, in characters.
+const MaxNameLength = 255
+
+// metadataReserved are the characters that delimit the metadata in a name and
+// therefore cannot appear in a key or a value.
+const metadataReserved = "{}=,"
+
+// EncodeNameMetadata returns the routing table name carrying the metadata
+// after the base name. The base name must not contain a brace, and keys and
+// values none of "{}=,"; keys must not be empty. It returns a
+// gophercloud.ErrInvalidInput if these rules are broken or if the result is
+// longer than MaxNameLength. Without metadata, the base name is returned.
+func EncodeNameMetadata(name string, metadata map[string]string) (string, error) {
+	if strings.ContainsAny(name, "{}") {
+		return "", invalidMetadata("name", name, "The base name cannot contain '{' or '}'")
+	}
+	if len(metadata) == 0 {
+		return name, nil
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for k, v := range metadata {
+		if k == "" {
+			return "", invalidMetadata("metadata", k, "Metadata keys cannot be empty")
+		}
+		if strings.ContainsAny(k, metadataReserved) {
+			return "", invalidMetadata("metadata", k, fmt.Sprintf("Metadata key %q cannot contain any of %q", k, metadataReserved))
+		}
+		if strings.ContainsAny(v, metadataReserved) {
+			return "", invalidMetadata("metadata", v, fmt.Sprintf("Metadata value of %q cannot contain any of %q", k, metadataReserved))
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+metadata[k])
+	}
+	encoded := name + "{" + strings.Join(pairs, ",") + "}"
+
+	if n := utf8.RuneCountInString(encoded); n > MaxNameLength {
+		return "", invalidMetadata("name", encoded, fmt.Sprintf("Name with metadata is %d characters long, the maximum is %d characters", n, MaxNameLength))
+	}
+	return encoded, nil
+}
+
+// DecodeNameMetadata splits a routing table name following the convention of
+// EncodeNameMetadata into its base name and its metadata. A name that does
+// not follow it is returned as is, with nil metadata.
+func DecodeNameMetadata(encoded string) (string, map[string]string) {
+	open := strings.IndexByte(encoded, '{')
+	if open < 0 || !strings.HasSuffix(encoded, "}") {
+		return encoded, nil
+	}
+
+	body := encoded[open+1 : len(encoded)-1]
+	if strings.ContainsAny(body, "{}") {
+		return encoded, nil
+	}
+
+	metadata := make(map[string]string)
+	if body != "" {
+		for _, pair := range strings.Split(body, ",") {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok || k == "" {
+				return encoded, nil
+			}
+			metadata[k] = v
+		}
+	}
+	return encoded[:open], metadata
+}
+
+// Metadata returns the metadata stored in the name of the routing table, see
+// DecodeNameMetadata.
+func (rt RoutingTable) Metadata() map[string]string {
+	_, metadata := DecodeNameMetadata(rt.Name)
+	return metadata
+}
+
+func invalidMetadata(argument, value, info string) error {
+	err := gophercloud.ErrInvalidInput{}
+	err.Argument = "routingtables.EncodeNameMetadata." + argument
+	err.Value = value
+	err.Info = info
+	return err
+}
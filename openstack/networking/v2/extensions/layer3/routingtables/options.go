@@ -0,0 +1,130 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package routingtables
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
+
+// RequestOption customizes a single routing table or route operation, e.g.
+// Get(c, id, WithTimeout(5*time.Second)).
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the settings collected from the RequestOptions of an
+// operation.
+type requestOptions struct {
+	timeout           time.Duration
+	headers           map[string]string
+	tenantID          string
+	checkDefaultTable bool
+
+	// skipConflictLookup is set by AttachGatewayWithRetry on the attempts it
+	// retries, see withoutConflictLookup.
+	skipConflictLookup bool
+}
+
+// collect applies the RequestOptions of an operation.
+func collect(reqOpts []RequestOption) requestOptions {
+	var o requestOptions
+	for _, apply := range reqOpts {
+		apply(&o)
+	}
+	return o
+}
+
+// WithTimeout bounds the duration of the operation, including retries and
+// reauthentication. It applies on top of any deadline of the context passed
+// to the WithContext variant, or of the provider client context.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithHeader sets an additional HTTP header on the requests of the operation.
+// It takes precedence over the service client MoreHeaders, but never over the
+// authentication headers, which the provider client sets last.
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[key] = value
+	}
+}
+
+// WithTenant scopes an operation to another tenant, which requires an admin
+// token: List and ListRoutes only return the routing tables and routes of the
+// tenant, and Create creates the routing table on its behalf. Other
+// operations ignore it.
+func WithTenant(tenantID string) RequestOption {
+	return func(o *requestOptions) {
+		o.tenantID = tenantID
+	}
+}
+
+// WithDefaultTableCheck makes DetachGateway and DetachGatewayAndVerify fetch
+// the routing table first and fail with an ErrCannotDetachDefault, without
+// sending the detach request, if it is the default routing table of its VPC.
+// Other operations ignore it.
+func WithDefaultTableCheck() RequestOption {
+	return func(o *requestOptions) {
+		o.checkDefaultTable = true
+	}
+}
+
+// withoutConflictLookup makes AttachGateway return a 409 Conflict as is,
+// without looking up the gateway to report an ErrGatewayAlreadyAttached.
+func withoutConflictLookup() RequestOption {
+	return func(o *requestOptions) {
+		o.skipConflictLookup = true
+	}
+}
+
+// withTenantQuery sets the tenant_id query parameter of a List URL.
+func withTenantQuery(listURL, tenantID string) (string, error) {
+	u, err := url.Parse(listURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("tenant_id", tenantID)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// prepare applies the context and the request options to the client, and
+// reports the start of the operation to the registered Hooks. The returned
+// function must be called with the error of the operation once it completed.
+func prepare(ctx context.Context, c *gophercloud.ServiceClient, operation string, reqOpts []RequestOption) (*gophercloud.ServiceClient, func(error)) {
+	o := collect(reqOpts)
+
+	cancel := context.CancelFunc(func() {})
+	if o.timeout > 0 {
+		if ctx == nil || ctx == context.Background() {
+			// Background means "inherit the provider context".
+			ctx = c.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+		}
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+	}
+
+	end := observe("routingtables." + operation)
+	c = withDebug(internal.WithHeaders(withContext(ctx, c), o.headers), "routingtables."+operation)
+	return c, func(err error) {
+		cancel()
+		end(err)
+	}
+}
@@ -8,7 +8,19 @@
 package routingtables
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path"
+	"regexp"
+
 	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internetgateways"
 	"github.com/cloud-barista/nhncloud-sdk-go/pagination"
 )
 
@@ -24,6 +36,12 @@ type ListOpts struct {
 	
 	// ID filters routing tables by ID
 	ID string `q:"id"`
+
+	// IDs filters routing tables by a set of IDs, sent as repeated id
+	// parameters. Regions that only honor a single id may ignore it; ListAll
+	// also filters the results client-side, List does not. If ID is also
+	// set, it is added to the set.
+	IDs []string `q:"id"`
 	
 	// Name filters routing tables by name
 	Name string `q:"name"`
@@ -34,27 +52,118 @@ type ListOpts struct {
 	// GatewayID filters routing tables by connected internet gateway ID
 	GatewayID string `q:"gateway_id"`
 	
+	// VPCID filters routing tables by the VPC they belong to. Not every
+	// region applies this filter server-side; ListAll also filters the
+	// results client-side, List does not.
+	VPCID string `q:"vpc_id"`
+	
 	// Distributed filters routing tables by routing type (true: distributed, false: centralized)
 	Distributed *bool `q:"distributed"`
 	
-	// Detail includes detailed information in the response
+	// Detail includes detailed information in the response. Subnets may still
+	// be returned as bare IDs without names; use DetailedList to have them
+	// resolved.
 	Detail *bool `q:"detail"`
 	
-	// SortDir specifies the sort direction (asc, desc)
+	// SortDir specifies the sort direction (SortAsc or SortDesc)
 	SortDir string `q:"sort_dir"`
 	
-	// SortKey specifies the field to sort by
+	// SortKey specifies the field to sort by (one of the SortKey constants)
 	SortKey string `q:"sort_key"`
+	
+	// Limit sets the maximum number of routing tables returned per page
+	Limit int `q:"limit"`
+	
+	// Marker is the ID of the last routing table of the previous page
+	Marker string `q:"marker"`
+}
+
+// Sort directions accepted by ListOpts.SortDir.
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// Sort keys accepted by ListOpts.SortKey.
+const (
+	SortKeyID           = "id"
+	SortKeyName         = "name"
+	SortKeyDefaultTable = "default_table"
+	SortKeyDistributed  = "distributed"
+	SortKeyGatewayID    = "gateway_id"
+	SortKeyGatewayName  = "gateway_name"
+	SortKeyTenantID     = "tenant_id"
+	SortKeyState        = "state"
+	SortKeyCreateTime   = "create_time"
+	SortKeyUpdateTime   = "update_time"
+)
+
+var validSortKeys = map[string]bool{
+	SortKeyID:           true,
+	SortKeyName:         true,
+	SortKeyDefaultTable: true,
+	SortKeyDistributed:  true,
+	SortKeyGatewayID:    true,
+	SortKeyGatewayName:  true,
+	SortKeyTenantID:     true,
+	SortKeyState:        true,
+	SortKeyCreateTime:   true,
+	SortKeyUpdateTime:   true,
 }
 
-// ToRoutingTableListQuery formats a ListOpts into a query string.
+// ToRoutingTableListQuery formats a ListOpts into a query string. It returns
+// a gophercloud.ErrInvalidInput if SortDir or SortKey is set to an unknown
+// value.
 func (opts ListOpts) ToRoutingTableListQuery() (string, error) {
+	if opts.SortDir != "" && opts.SortDir != SortAsc && opts.SortDir != SortDesc {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "routingtables.ListOpts.SortDir"
+		err.Value = opts.SortDir
+		err.Info = fmt.Sprintf("SortDir must be %q or %q", SortAsc, SortDesc)
+		return "", err
+	}
+	if opts.SortKey != "" && !validSortKeys[opts.SortKey] {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "routingtables.ListOpts.SortKey"
+		err.Value = opts.SortKey
+		err.Info = fmt.Sprintf("SortKey %q is not a routing table field", opts.SortKey)
+		return "", err
+	}
+
+	if ids := opts.idSet(); ids != nil {
+		opts.ID, opts.IDs = "", ids
+	}
 	q, err := gophercloud.BuildQueryString(opts)
 	return q.String(), err
 }
 
+// idSet returns IDs with ID added to it, without duplicates, or nil if IDs is
+// empty; ID alone is sent as a single id parameter.
+func (opts ListOpts) idSet() []string {
+	if len(opts.IDs) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(opts.IDs)+1)
+	seen := make(map[string]bool, len(opts.IDs)+1)
+	for _, id := range append([]string{opts.ID}, opts.IDs...) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // List returns a Pager which allows you to iterate over a collection of routing tables.
-func List(c *gophercloud.ServiceClient, opts ListOptsBuilder) pagination.Pager {
+// Only the WithHeader and WithTenant request options apply to it.
+func List(c *gophercloud.ServiceClient, opts ListOptsBuilder, reqOpts ...RequestOption) pagination.Pager {
+	return ListWithContext(context.Background(), c, opts, reqOpts...)
+}
+
+// ListWithContext is the context-aware variant of List.
+func ListWithContext(ctx context.Context, c *gophercloud.ServiceClient, opts ListOptsBuilder, reqOpts ...RequestOption) pagination.Pager {
+	o := collect(reqOpts)
+	c = withDebug(internal.WithHeaders(withContext(ctx, c), o.headers), "routingtables.List")
 	url := listURL(c)
 	if opts != nil {
 		query, err := opts.ToRoutingTableListQuery()
@@ -63,15 +172,49 @@ func List(c *gophercloud.ServiceClient, opts ListOptsBuilder) pagination.Pager {
 		}
 		url += query
 	}
+	if o.tenantID != "" {
+		var err error
+		if url, err = withTenantQuery(url, o.tenantID); err != nil {
+			return pagination.Pager{Err: err}
+		}
+	}
 	return pagination.NewPager(c, url, func(r pagination.PageResult) pagination.Page {
 		return RoutingTablePage{pagination.LinkedPageBase{PageResult: r}}
 	})
 }
 
 // Get retrieves a specific routing table based on its unique ID.
-func Get(c *gophercloud.ServiceClient, id string) (r GetResult) {
-	resp, err := c.Get(resourceURL(c, id), &r.Body, nil)
-	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+func Get(c *gophercloud.ServiceClient, id string, reqOpts ...RequestOption) (r GetResult) {
+	return GetWithContext(context.Background(), c, id, reqOpts...)
+}
+
+// GetWithContext is the context-aware variant of Get.
+func GetWithContext(ctx context.Context, c *gophercloud.ServiceClient, id string, reqOpts ...RequestOption) (r GetResult) {
+	c, done := prepare(ctx, c, "Get", reqOpts)
+	defer func() { done(r.Err) }()
+	resp, err := c.Get(resourceURL(c, id), nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		ErrorContext:     ErrRoutingTable{ID: id},
+	})
+	r.readResponse(resp, err)
+	return
+}
+
+// GetDetailed is the same as Get, but requests the detailed view of the
+// routing table, in which the VPCs and subnets are fully populated.
+func GetDetailed(c *gophercloud.ServiceClient, id string, reqOpts ...RequestOption) (r GetResult) {
+	return GetDetailedWithContext(context.Background(), c, id, reqOpts...)
+}
+
+// GetDetailedWithContext is the context-aware variant of GetDetailed.
+func GetDetailedWithContext(ctx context.Context, c *gophercloud.ServiceClient, id string, reqOpts ...RequestOption) (r GetResult) {
+	c, done := prepare(ctx, c, "GetDetailed", reqOpts)
+	defer func() { done(r.Err) }()
+	resp, err := c.Get(resourceURL(c, id)+"?detail=true", nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		ErrorContext:     ErrRoutingTable{ID: id},
+	})
+	r.readResponse(resp, err)
 	return
 }
 
@@ -91,22 +234,93 @@ type CreateOpts struct {
 	// Distributed specifies the routing type (true: distributed, false: centralized)
 	// Defaults to true if not specified
 	Distributed *bool `json:"distributed,omitempty"`
+
+	// TenantID is the tenant that will own the routing table. Only
+	// administrators can create a routing table on behalf of another tenant.
+	TenantID string `json:"tenant_id,omitempty"`
 }
 
-// ToRoutingTableCreateMap builds a request body from CreateOpts.
+// NewCentralizedRoutingTable returns the options to create a centralized
+// routing table, with Distributed set explicitly rather than left to the
+// server default.
+func NewCentralizedRoutingTable(name, vpcID string) CreateOpts {
+	distributed := false
+	return CreateOpts{Name: name, VPCID: vpcID, Distributed: &distributed}
+}
+
+// NewDistributedRoutingTable returns the options to create a distributed
+// routing table, with Distributed set explicitly rather than left to the
+// server default.
+func NewDistributedRoutingTable(name, vpcID string) CreateOpts {
+	distributed := true
+	return CreateOpts{Name: name, VPCID: vpcID, Distributed: &distributed}
+}
+
+// Validate checks that Name and VPCID are set, and returns a
+// gophercloud.ErrMissingInput naming the missing field otherwise.
+func (opts CreateOpts) Validate() error {
+	if opts.Name == "" {
+		err := gophercloud.ErrMissingInput{Argument: "routingtables.CreateOpts.Name"}
+		err.Info = "name is required"
+		return err
+	}
+	if opts.VPCID == "" {
+		err := gophercloud.ErrMissingInput{Argument: "routingtables.CreateOpts.VPCID"}
+		err.Info = "vpc_id is required"
+		return err
+	}
+	return nil
+}
+
+// ToRoutingTableCreateMap builds a request body from CreateOpts, after
+// checking it with Validate.
 func (opts CreateOpts) ToRoutingTableCreateMap() (map[string]interface{}, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 	return gophercloud.BuildRequestBody(opts, "routingtable")
 }
 
 // Create accepts a CreateOpts struct and creates a new routing table using the values provided.
-func Create(c *gophercloud.ServiceClient, opts CreateOptsBuilder) (r CreateResult) {
+// The options are validated before any request is sent.
+//
+// If the response omits the VPCs of the routing table, they are filled in on
+// the client side with the requested VPC ID, so the result can be used right
+// away; the VPC name is then left empty.
+func Create(c *gophercloud.ServiceClient, opts CreateOptsBuilder, reqOpts ...RequestOption) (r CreateResult) {
+	return CreateWithContext(context.Background(), c, opts, reqOpts...)
+}
+
+// CreateWithContext is the context-aware variant of Create.
+func CreateWithContext(ctx context.Context, c *gophercloud.ServiceClient, opts CreateOptsBuilder, reqOpts ...RequestOption) (r CreateResult) {
+	c, done := prepare(ctx, c, "Create", reqOpts)
+	defer func() { done(r.Err) }()
 	b, err := opts.ToRoutingTableCreateMap()
 	if err != nil {
 		r.Err = err
 		return
 	}
-	resp, err := c.Post(createURL(c), b, &r.Body, nil)
-	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	errCtx := ErrRoutingTableCreate{}
+	requested, _ := b["routingtable"].(map[string]interface{})
+	if tenantID := collect(reqOpts).tenantID; tenantID != "" && requested != nil {
+		requested["tenant_id"] = tenantID
+	}
+	errCtx.Name, _ = requested["name"].(string)
+	resp, err := c.Post(createURL(c), b, nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		ErrorContext:     errCtx,
+	})
+	r.readResponse(resp, err)
+	if r.Err != nil {
+		return
+	}
+
+	vpcID, _ := requested["vpc_id"].(string)
+	body, _ := r.Body.(map[string]interface{})
+	created, _ := body["routingtable"].(map[string]interface{})
+	if vpcs, _ := created["vpcs"].([]interface{}); created != nil && len(vpcs) == 0 && vpcID != "" {
+		created["vpcs"] = []interface{}{vpcID}
+	}
 	return
 }
 
@@ -130,19 +344,74 @@ func (opts UpdateOpts) ToRoutingTableUpdateMap() (map[string]interface{}, error)
 }
 
 // Update accepts a UpdateOpts struct and updates an existing routing table using the values provided.
-func Update(c *gophercloud.ServiceClient, routingtableID string, opts UpdateOptsBuilder) (r UpdateResult) {
+//
+// The API may accept a change of the routing type without applying it, for
+// instance while resources are attached to the routing table. If the request
+// sets "distributed", the returned routing table is checked and the result
+// holds an ErrDistributedUnchanged if the routing type differs.
+func Update(c *gophercloud.ServiceClient, routingtableID string, opts UpdateOptsBuilder, reqOpts ...RequestOption) (r UpdateResult) {
+	return UpdateWithContext(context.Background(), c, routingtableID, opts, reqOpts...)
+}
+
+// UpdateWithContext is the context-aware variant of Update.
+func UpdateWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, opts UpdateOptsBuilder, reqOpts ...RequestOption) (r UpdateResult) {
+	c, done := prepare(ctx, c, "Update", reqOpts)
+	defer func() { done(r.Err) }()
 	b, err := opts.ToRoutingTableUpdateMap()
 	if err != nil {
 		r.Err = err
 		return
 	}
-	resp, err := c.Put(resourceURL(c, routingtableID), b, &r.Body, nil)
-	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	resp, err := c.Put(resourceURL(c, routingtableID), b, nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		OkCodes:          []int{200, 201, 202},
+	})
+	r.readResponse(resp, err)
+	if r.Err != nil {
+		return
+	}
+
+	body, _ := b["routingtable"].(map[string]interface{})
+	distributed, ok := body["distributed"].(bool)
+	if !ok {
+		return
+	}
+	rt, err := r.Extract()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	if rt != nil && rt.Distributed != distributed {
+		r.Err = ErrDistributedUnchanged{ID: routingtableID, Requested: distributed, Reasons: distributedBlockers(rt)}
+	}
 	return
 }
 
+// distributedBlockers lists the likely reasons why the routing type of the
+// routing table could not be changed.
+func distributedBlockers(rt *RoutingTable) []string {
+	var reasons []string
+	if rt.State != "" && RoutingTableState(rt.State) != StateAvailable {
+		reasons = append(reasons, fmt.Sprintf("the routing table is %s", rt.State))
+	}
+	if rt.GatewayID != "" {
+		reasons = append(reasons, fmt.Sprintf("internet gateway [%s] is attached", rt.GatewayID))
+	}
+	if n := len(rt.Subnets); n > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d subnet(s) are associated", n))
+	}
+	return reasons
+}
+
 // Delete accepts a unique ID and deletes the routing table associated with it.
-func Delete(c *gophercloud.ServiceClient, routingtableID string) (r DeleteResult) {
+func Delete(c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r DeleteResult) {
+	return DeleteWithContext(context.Background(), c, routingtableID, reqOpts...)
+}
+
+// DeleteWithContext is the context-aware variant of Delete.
+func DeleteWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r DeleteResult) {
+	c, done := prepare(ctx, c, "Delete", reqOpts)
+	defer func() { done(r.Err) }()
 	resp, err := c.Delete(resourceURL(c, routingtableID), nil)
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
@@ -157,6 +426,12 @@ type AttachGatewayOptsBuilder interface {
 type AttachGatewayOpts struct {
 	// GatewayID is the ID of the internet gateway to attach
 	GatewayID string `json:"gateway_id" required:"true"`
+
+	// VerifyGateway makes AttachGateway check that the internet gateway
+	// exists before attaching it, at the cost of an extra request. A missing
+	// gateway is then reported as ErrGatewayNotFound instead of a 404 on the
+	// routing table.
+	VerifyGateway bool `json:"-"`
 }
 
 // ToAttachGatewayMap builds a request body from AttachGatewayOpts.
@@ -164,43 +439,309 @@ func (opts AttachGatewayOpts) ToAttachGatewayMap() (map[string]interface{}, erro
 	return gophercloud.BuildRequestBody(opts, "")
 }
 
+// gatewayToVerify returns the ID of the internet gateway that AttachGateway
+// must check before attaching it, if opts asks for it with VerifyGateway.
+func gatewayToVerify(opts AttachGatewayOptsBuilder) (gatewayID string, verify bool) {
+	switch o := opts.(type) {
+	case AttachGatewayOpts:
+		return o.GatewayID, o.VerifyGateway
+	case *AttachGatewayOpts:
+		return o.GatewayID, o.VerifyGateway
+	}
+	return "", false
+}
+
 // AttachGateway attaches an internet gateway to a routing table.
-func AttachGateway(c *gophercloud.ServiceClient, routingtableID string, opts AttachGatewayOptsBuilder) (r AttachGatewayResult) {
+//
+// An internet gateway serves a single routing table; attaching a gateway that
+// is attached to another routing table is reported as an
+// ErrGatewayAlreadyAttached.
+func AttachGateway(c *gophercloud.ServiceClient, routingtableID string, opts AttachGatewayOptsBuilder, reqOpts ...RequestOption) (r AttachGatewayResult) {
+	return AttachGatewayWithContext(context.Background(), c, routingtableID, opts, reqOpts...)
+}
+
+// AttachGatewayWithContext is the context-aware variant of AttachGateway.
+func AttachGatewayWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, opts AttachGatewayOptsBuilder, reqOpts ...RequestOption) (r AttachGatewayResult) {
+	c, done := prepare(ctx, c, "AttachGateway", reqOpts)
+	defer func() { done(r.Err) }()
 	b, err := opts.ToAttachGatewayMap()
 	if err != nil {
 		r.Err = err
 		return
 	}
-	resp, err := c.Put(attachGatewayURL(c, routingtableID), b, &r.Body, &gophercloud.RequestOpts{
-		OkCodes: []int{200},
+	if gatewayID, verify := gatewayToVerify(opts); verify {
+		_, err := getGateway(c, gatewayID)
+		if _, ok := err.(gophercloud.ErrDefault404); ok {
+			r.Err = ErrGatewayNotFound{RoutingTableID: routingtableID, GatewayID: gatewayID}
+			return
+		}
+		if err != nil {
+			r.Err = err
+			return
+		}
+	}
+	resp, err := c.Put(attachGatewayURL(c, routingtableID), b, nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		OkCodes:          []int{200},
 	})
-	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	r.readResponse(resp, err)
+	if conflict, ok := r.Err.(gophercloud.ErrDefault409); ok && !collect(reqOpts).skipConflictLookup {
+		if gatewayID, _ := b["gateway_id"].(string); gatewayID != "" {
+			r.Err = gatewayAlreadyAttached(c, routingtableID, gatewayID, conflict)
+		}
+	}
 	return
 }
 
+// gatewayAlreadyAttached translates the 409 Conflict returned when attaching
+// an internet gateway into an ErrGatewayAlreadyAttached if the gateway turns
+// out to be attached to another routing table. Otherwise the conflict is
+// returned as is: the API also answers 409 while the routing table is busy,
+// which AttachGatewayWithRetry retries.
+func gatewayAlreadyAttached(c *gophercloud.ServiceClient, routingtableID, gatewayID string, conflict gophercloud.ErrDefault409) error {
+	igw, err := getGateway(c, gatewayID)
+	if err != nil {
+		return conflict
+	}
+	attached, ok := igw.AttachedRoutingTableID()
+	if !ok || attached == routingtableID {
+		return conflict
+	}
+	return ErrGatewayAlreadyAttached{
+		ErrUnexpectedResponseCode: conflict.ErrUnexpectedResponseCode,
+		RoutingTableID:            routingtableID,
+		GatewayID:                 gatewayID,
+		AttachedRoutingTableID:    attached,
+	}
+}
+
+// getGateway fetches an internet gateway on behalf of a routing table
+// operation, reporting it to the Hooks as "routingtables.GetGateway".
+func getGateway(c *gophercloud.ServiceClient, gatewayID string) (igw *internetgateways.InternetGateway, err error) {
+	end := observe("routingtables.GetGateway")
+	defer func() { end(err) }()
+	return internetgateways.Get(c, gatewayID).Extract()
+}
+
 // DetachGateway detaches an internet gateway from a routing table.
-func DetachGateway(c *gophercloud.ServiceClient, routingtableID string) (r DetachGatewayResult) {
-	resp, err := c.Put(detachGatewayURL(c, routingtableID), nil, &r.Body, &gophercloud.RequestOpts{
-		OkCodes: []int{200},
+//
+// The API refuses to detach the gateway of a default routing table; this is
+// reported as an ErrCannotDetachDefault, which WithDefaultTableCheck can
+// also detect before sending the request.
+func DetachGateway(c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r DetachGatewayResult) {
+	return DetachGatewayWithContext(context.Background(), c, routingtableID, reqOpts...)
+}
+
+// DetachGatewayWithContext is the context-aware variant of DetachGateway.
+func DetachGatewayWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r DetachGatewayResult) {
+	c, done := prepare(ctx, c, "DetachGateway", reqOpts)
+	defer func() { done(r.Err) }()
+	if r.Err = checkNotDefault(c, routingtableID, reqOpts); r.Err != nil {
+		return
+	}
+	resp, err := c.Put(detachGatewayURL(c, routingtableID), nil, nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		OkCodes:          []int{200},
+		ErrorContext:     ErrGatewayDetach{RoutingTableID: routingtableID},
 	})
-	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	r.readResponse(resp, err)
 	return
 }
 
 // SetAsDefault sets a routing table as the default routing table for its VPC.
-func SetAsDefault(c *gophercloud.ServiceClient, routingtableID string) (r SetAsDefaultResult) {
-	resp, err := c.Put(setAsDefaultURL(c, routingtableID), nil, &r.Body, nil)
+func SetAsDefault(c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r SetAsDefaultResult) {
+	return SetAsDefaultWithContext(context.Background(), c, routingtableID, reqOpts...)
+}
+
+// SetAsDefaultWithContext is the context-aware variant of SetAsDefault.
+//
+// Some API versions answer with an empty body; the routing table is then
+// fetched again. In both cases the result holds an ErrRoutingTableNotDefault
+// if the returned routing table is not flagged as the default one, and an
+// ErrMissingRoutingTable if the response does not hold it.
+func SetAsDefaultWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r SetAsDefaultResult) {
+	c, done := prepare(ctx, c, "SetAsDefault", reqOpts)
+	defer func() { done(r.Err) }()
+	r.RoutingTableResult = putAction(c, setAsDefaultURL(c, routingtableID), routingtableID, nil)
+	if r.Err != nil {
+		return
+	}
+
+	rt, err := r.Extract()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	if rt == nil {
+		r.Err = ErrMissingRoutingTable{ID: routingtableID}
+		return
+	}
+	if !rt.DefaultTable {
+		r.Err = ErrRoutingTableNotDefault{ID: routingtableID}
+	}
+	return
+}
+
+// DetachGatewayAndVerify detaches the internet gateway from a routing table
+// and checks that the returned routing table no longer references a gateway.
+// Empty responses are handled by fetching the routing table again. If a
+// gateway is still attached, the result holds an ErrGatewayStillAttached, and
+// if the response does not hold the routing table, an ErrMissingRoutingTable.
+func DetachGatewayAndVerify(c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r DetachGatewayResult) {
+	return DetachGatewayAndVerifyWithContext(context.Background(), c, routingtableID, reqOpts...)
+}
+
+// DetachGatewayAndVerifyWithContext is the context-aware variant of DetachGatewayAndVerify.
+func DetachGatewayAndVerifyWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r DetachGatewayResult) {
+	c, done := prepare(ctx, c, "DetachGatewayAndVerify", reqOpts)
+	defer func() { done(r.Err) }()
+	if r.Err = checkNotDefault(c, routingtableID, reqOpts); r.Err != nil {
+		return
+	}
+	r.RoutingTableResult = putAction(c, detachGatewayURL(c, routingtableID), routingtableID, ErrGatewayDetach{RoutingTableID: routingtableID})
+	if r.Err != nil {
+		return
+	}
+
+	rt, err := r.Extract()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	if rt == nil {
+		r.Err = ErrMissingRoutingTable{ID: routingtableID}
+		return
+	}
+	if rt.GatewayID != "" {
+		r.Err = ErrGatewayStillAttached{ID: routingtableID, GatewayID: rt.GatewayID}
+	}
+	return
+}
+
+// checkNotDefault returns an ErrCannotDetachDefault if WithDefaultTableCheck
+// is among the request options and the routing table is a default one.
+func checkNotDefault(c *gophercloud.ServiceClient, routingtableID string, reqOpts []RequestOption) error {
+	if !collect(reqOpts).checkDefaultTable {
+		return nil
+	}
+	rt, err := Get(c, routingtableID).Extract()
+	if err != nil {
+		return err
+	}
+	if rt == nil {
+		return ErrMissingRoutingTable{ID: routingtableID}
+	}
+	if rt.DefaultTable {
+		return ErrCannotDetachDefault{RoutingTableID: routingtableID}
+	}
+	return nil
+}
+
+// putAction issues a body-less PUT request for an action on a routing table,
+// with errCtx as error context if it is not nil. Some API versions answer
+// such actions with an empty body; the routing table is then fetched again so
+// the result always holds it.
+func putAction(c *gophercloud.ServiceClient, url, routingtableID string, errCtx error) (r RoutingTableResult) {
+	resp, err := c.Put(url, nil, nil, &gophercloud.RequestOpts{
+		OkCodes:          []int{200, 201, 202, 204},
+		KeepResponseBody: true,
+		ErrorContext:     errCtx,
+	})
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	if r.Err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	var s map[string]interface{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &s); err != nil {
+			r.Err = err
+			return
+		}
+	}
+	if _, ok := s["routingtable"]; ok {
+		r.Body, r.raw = s, body
+		return
+	}
+
+	current := Get(c, routingtableID)
+	r.Body, r.raw, r.Err = current.Body, current.raw, current.Err
 	return
 }
 
+// readResponse completes a routing table result from the response of a
+// request sent with KeepResponseBody, keeping the body as received for
+// RawBody. Like gophercloud, it decodes any body except that of a 204.
+func (r *RoutingTableResult) readResponse(resp *http.Response, err error) {
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	if r.Err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+	if r.raw, r.Err = io.ReadAll(resp.Body); r.Err != nil || resp.StatusCode == http.StatusNoContent {
+		return
+	}
+	r.Err = json.Unmarshal(r.raw, &r.Body)
+}
+
 // GetRelatedGateways retrieves gateways that can be reached through the routing policies set in the routing table.
-func GetRelatedGateways(c *gophercloud.ServiceClient, routingtableID string) (r GetRelatedGatewaysResult) {
+func GetRelatedGateways(c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r GetRelatedGatewaysResult) {
+	return GetRelatedGatewaysWithContext(context.Background(), c, routingtableID, reqOpts...)
+}
+
+// GetRelatedGatewaysWithContext is the context-aware variant of GetRelatedGateways.
+func GetRelatedGatewaysWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, reqOpts ...RequestOption) (r GetRelatedGatewaysResult) {
+	c, done := prepare(ctx, c, "GetRelatedGateways", reqOpts)
+	defer func() { done(r.Err) }()
 	resp, err := c.Get(relatedGatewaysURL(c, routingtableID), &r.Body, nil)
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
 }
 
+// AssociateSubnet connects a subnet to a routing table.
+func AssociateSubnet(c *gophercloud.ServiceClient, routingtableID string, subnetID string, reqOpts ...RequestOption) (r AssociateSubnetResult) {
+	return AssociateSubnetWithContext(context.Background(), c, routingtableID, subnetID, reqOpts...)
+}
+
+// AssociateSubnetWithContext is the context-aware variant of AssociateSubnet.
+func AssociateSubnetWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, subnetID string, reqOpts ...RequestOption) (r AssociateSubnetResult) {
+	c, done := prepare(ctx, c, "AssociateSubnet", reqOpts)
+	defer func() { done(r.Err) }()
+	b := map[string]interface{}{"subnet_id": subnetID}
+	resp, err := c.Put(attachSubnetURL(c, routingtableID), b, nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		OkCodes:          []int{200},
+	})
+	r.readResponse(resp, err)
+	return
+}
+
+// DisassociateSubnet disconnects a subnet from a routing table.
+func DisassociateSubnet(c *gophercloud.ServiceClient, routingtableID string, subnetID string, reqOpts ...RequestOption) (r DisassociateSubnetResult) {
+	return DisassociateSubnetWithContext(context.Background(), c, routingtableID, subnetID, reqOpts...)
+}
+
+// DisassociateSubnetWithContext is the context-aware variant of DisassociateSubnet.
+func DisassociateSubnetWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, subnetID string, reqOpts ...RequestOption) (r DisassociateSubnetResult) {
+	c, done := prepare(ctx, c, "DisassociateSubnet", reqOpts)
+	defer func() { done(r.Err) }()
+	b := map[string]interface{}{"subnet_id": subnetID}
+	resp, err := c.Put(detachSubnetURL(c, routingtableID), b, nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		OkCodes:          []int{200},
+	})
+	r.readResponse(resp, err)
+	return
+}
+
 // Route management functions
 
 // RouteListOptsBuilder allows extensions to add additional parameters to the route List request.
@@ -216,7 +757,8 @@ type RouteListOpts struct {
 	// CIDR filters routes by destination CIDR
 	CIDR string `q:"cidr"`
 	
-	// Mask filters routes by destination CIDR netmask (0-32)
+	// Mask filters routes by destination CIDR prefix length (0-32 for IPv4,
+	// 0-128 for IPv6)
 	Mask *int `q:"mask"`
 	
 	// Gateway filters routes by gateway IP
@@ -227,6 +769,12 @@ type RouteListOpts struct {
 	
 	// GatewayID filters routes by internet gateway ID
 	GatewayID string `q:"gateway_id"`
+	
+	// Limit sets the maximum number of routes returned per page
+	Limit int `q:"limit"`
+	
+	// Marker is the ID of the last route of the previous page
+	Marker string `q:"marker"`
 }
 
 // ToRouteListQuery formats a RouteListOpts into a query string.
@@ -236,7 +784,15 @@ func (opts RouteListOpts) ToRouteListQuery() (string, error) {
 }
 
 // ListRoutes returns a Pager which allows you to iterate over a collection of routes.
-func ListRoutes(c *gophercloud.ServiceClient, opts RouteListOptsBuilder) pagination.Pager {
+// Only the WithHeader and WithTenant request options apply to it.
+func ListRoutes(c *gophercloud.ServiceClient, opts RouteListOptsBuilder, reqOpts ...RequestOption) pagination.Pager {
+	return ListRoutesWithContext(context.Background(), c, opts, reqOpts...)
+}
+
+// ListRoutesWithContext is the context-aware variant of ListRoutes.
+func ListRoutesWithContext(ctx context.Context, c *gophercloud.ServiceClient, opts RouteListOptsBuilder, reqOpts ...RequestOption) pagination.Pager {
+	o := collect(reqOpts)
+	c = withDebug(internal.WithHeaders(withContext(ctx, c), o.headers), "routingtables.ListRoutes")
 	url := routesURL(c)
 	if opts != nil {
 		query, err := opts.ToRouteListQuery()
@@ -245,13 +801,31 @@ func ListRoutes(c *gophercloud.ServiceClient, opts RouteListOptsBuilder) paginat
 		}
 		url += query
 	}
+	if o.tenantID != "" {
+		var err error
+		if url, err = withTenantQuery(url, o.tenantID); err != nil {
+			return pagination.Pager{Err: err}
+		}
+	}
+	return newRoutePager(c, url)
+}
+
+// newRoutePager returns a Pager over the routes starting at url.
+func newRoutePager(c *gophercloud.ServiceClient, url string) pagination.Pager {
 	return pagination.NewPager(c, url, func(r pagination.PageResult) pagination.Page {
 		return RoutePage{pagination.LinkedPageBase{PageResult: r}}
 	})
 }
 
 // GetRoute retrieves a specific route based on its unique ID.
-func GetRoute(c *gophercloud.ServiceClient, routeID string) (r GetRouteResult) {
+func GetRoute(c *gophercloud.ServiceClient, routeID string, reqOpts ...RequestOption) (r GetRouteResult) {
+	return GetRouteWithContext(context.Background(), c, routeID, reqOpts...)
+}
+
+// GetRouteWithContext is the context-aware variant of GetRoute.
+func GetRouteWithContext(ctx context.Context, c *gophercloud.ServiceClient, routeID string, reqOpts ...RequestOption) (r GetRouteResult) {
+	c, done := prepare(ctx, c, "GetRoute", reqOpts)
+	defer func() { done(r.Err) }()
 	resp, err := c.Get(routeURL(c, routeID), &r.Body, nil)
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
@@ -273,27 +847,237 @@ type CreateRouteOpts struct {
 	// Gateway is the gateway IP for the route
 	Gateway string `json:"gateway" required:"true"`
 	
-	// Description is the description of the route (max 256 bytes)
-	Description string `json:"description" required:"true"`
+	// Description is the optional description of the route (max 256 bytes)
+	Description string `json:"description,omitempty"`
+}
+
+// Validate checks that CIDR is a valid IPv4 or IPv6 CIDR, that Gateway is a
+// valid IP address and that Description fits in 256 bytes, so malformed
+// routes are rejected before any request.
+func (opts CreateRouteOpts) Validate() error {
+	if _, _, err := net.ParseCIDR(opts.CIDR); err != nil {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "routingtables.CreateRouteOpts.CIDR"
+		err.Value = opts.CIDR
+		err.Info = fmt.Sprintf("CIDR %q is not a valid CIDR notation", opts.CIDR)
+		return err
+	}
+
+	gateway := net.ParseIP(opts.Gateway)
+	if gateway == nil {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "routingtables.CreateRouteOpts.Gateway"
+		err.Value = opts.Gateway
+		err.Info = fmt.Sprintf("Gateway %q is not a valid IP address", opts.Gateway)
+		return err
+	}
+
+	if isIPv6CIDR(opts.CIDR) != (gateway.To4() == nil) {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = "routingtables.CreateRouteOpts.Gateway"
+		err.Value = opts.Gateway
+		err.Info = fmt.Sprintf("Gateway %q and CIDR %q are not of the same IP version", opts.Gateway, opts.CIDR)
+		return err
+	}
+
+	return validateDescription("routingtables.CreateRouteOpts.Description", opts.Description)
+}
+
+// maxDescriptionLength is the maximum length of a route description, in bytes.
+const maxDescriptionLength = 256
+
+// validateDescription checks that a route description fits the API limit,
+// which is counted in bytes rather than characters.
+func validateDescription(argument, description string) error {
+	if n := len(description); n > maxDescriptionLength {
+		err := gophercloud.ErrInvalidInput{}
+		err.Argument = argument
+		err.Value = description
+		err.Info = fmt.Sprintf("Description is %d bytes long, the maximum is %d bytes", n, maxDescriptionLength)
+		return err
+	}
+	return nil
+}
+
+// isIPv6CIDR reports whether cidr is an IPv6 CIDR.
+func isIPv6CIDR(cidr string) bool {
+	ip, _, err := net.ParseCIDR(cidr)
+	return err == nil && ip.To4() == nil
 }
 
 // ToRouteCreateMap builds a request body from CreateRouteOpts.
 func (opts CreateRouteOpts) ToRouteCreateMap() (map[string]interface{}, error) {
-	return gophercloud.BuildRequestBody(opts, "route")
+	b, err := gophercloud.BuildRequestBody(opts, "route")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
 }
 
 // CreateRoute accepts a CreateRouteOpts struct and creates a new route using the values provided.
-func CreateRoute(c *gophercloud.ServiceClient, opts CreateRouteOptsBuilder) (r CreateRouteResult) {
+//
+// Some regions answer with an empty body. The route is then retrieved through
+// the Location header of the response or, failing that, by listing the routes
+// of the routing table with the same CIDR and gateway. If neither identifies
+// a single route, the result holds an ErrRouteNotRecovered.
+func CreateRoute(c *gophercloud.ServiceClient, opts CreateRouteOptsBuilder, reqOpts ...RequestOption) (r CreateRouteResult) {
+	return CreateRouteWithContext(context.Background(), c, opts, reqOpts...)
+}
+
+// CreateRouteWithContext is the context-aware variant of CreateRoute.
+func CreateRouteWithContext(ctx context.Context, c *gophercloud.ServiceClient, opts CreateRouteOptsBuilder, reqOpts ...RequestOption) (r CreateRouteResult) {
+	c, done := prepare(ctx, c, "CreateRoute", reqOpts)
+	defer func() { done(r.Err) }()
 	b, err := opts.ToRouteCreateMap()
 	if err != nil {
 		r.Err = err
 		return
 	}
-	resp, err := c.Post(routesURL(c), b, &r.Body, nil)
+	postOpts := &gophercloud.RequestOpts{KeepResponseBody: true}
+	route, _ := b["route"].(map[string]interface{})
+	routingtableID, _ := route["routingtable_id"].(string)
+	cidr, _ := route["cidr"].(string)
+	gateway, _ := route["gateway"].(string)
+	if isIPv6CIDR(cidr) {
+		postOpts.ErrorContext = ErrIPv6Route{CIDR: cidr}
+	}
+	resp, err := c.Post(routesURL(c), b, nil, postOpts)
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	if r.Err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	var s map[string]interface{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &s); err != nil {
+			r.Err = err
+			return
+		}
+	}
+	if _, ok := s["route"]; ok {
+		r.Body = s
+		return
+	}
+
+	id := path.Base(resp.Header.Get("Location"))
+	if id == "." || id == "/" {
+		id, err = findCreatedRoute(c, routingtableID, cidr, gateway)
+		if err != nil {
+			r.Err = err
+			return
+		}
+	}
+	created := GetRoute(c, id)
+	r.Body, r.Err = created.Body, created.Err
 	return
 }
 
+// findCreatedRoute returns the ID of the only route of the routing table with
+// the given CIDR and gateway.
+func findCreatedRoute(c *gophercloud.ServiceClient, routingtableID, cidr, gateway string) (string, error) {
+	notRecovered := ErrRouteNotRecovered{RoutingTableID: routingtableID, CIDR: cidr, Gateway: gateway}
+
+	routes, err := ListAllRoutes(c, RouteListOpts{RoutingTableID: routingtableID, CIDR: cidr, Gateway: gateway})
+	if err != nil {
+		notRecovered.Err = err
+		return "", notRecovered
+	}
+
+	want := Route{CIDR: cidr}
+	wantCIDR, _ := want.NormalizedCIDR()
+	var ids []string
+	for _, route := range routes {
+		got, err := route.NormalizedCIDR()
+		if err == nil && got == wantCIDR && route.Gateway == gateway {
+			ids = append(ids, route.ID)
+		}
+	}
+	if len(ids) != 1 {
+		notRecovered.Matches = len(ids)
+		return "", notRecovered
+	}
+	return ids[0], nil
+}
+
+// BulkCreateRoutes creates several routes in a routing table with a single
+// request carrying a "routes" array. If the API does not support such batch
+// requests, the routes are created one at a time instead; the routes created
+// before the first failure are returned together with that failure. Other
+// errors, such as a validation error of one of the routes, are returned as is.
+func BulkCreateRoutes(c *gophercloud.ServiceClient, routingtableID string, opts []CreateRouteOpts, reqOpts ...RequestOption) ([]Route, error) {
+	return BulkCreateRoutesWithContext(context.Background(), c, routingtableID, opts, reqOpts...)
+}
+
+// BulkCreateRoutesWithContext is the context-aware variant of BulkCreateRoutes.
+func BulkCreateRoutesWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, opts []CreateRouteOpts, reqOpts ...RequestOption) (created []Route, err error) {
+	c, done := prepare(ctx, c, "BulkCreateRoutes", reqOpts)
+	defer func() { done(err) }()
+
+	routes := make([]interface{}, 0, len(opts))
+	for _, o := range opts {
+		o.RoutingTableID = routingtableID
+		b, err := o.ToRouteCreateMap()
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, b["route"])
+	}
+
+	var r BulkCreateRoutesResult
+	resp, err := c.Post(routesURL(c), map[string]interface{}{"routes": routes}, &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	if bulkUnsupported(r.Err) {
+		return createRoutesSequentially(c, routingtableID, opts)
+	}
+	return r.Extract()
+}
+
+// bulkUnsupportedPattern matches the Neutron error messages of an API that
+// does not accept a "routes" array, such as "Bulk operation not supported" or
+// "Unable to find 'route' in request body".
+var bulkUnsupportedPattern = regexp.MustCompile(`(?i)\bbulk\b.*\b(not supported|unsupported)\b|unable to find 'route' in request body`)
+
+// bulkUnsupported reports whether a BulkCreateRoutes request failed because
+// the API does not support batch requests: a 404 or 405, or a 400 whose
+// message says so.
+func bulkUnsupported(err error) bool {
+	switch e := err.(type) {
+	case gophercloud.ErrDefault404, gophercloud.ErrDefault405:
+		return true
+	case gophercloud.ErrDefault400:
+		_, message, ok := e.NeutronError()
+		return ok && bulkUnsupportedPattern.MatchString(message)
+	}
+	return false
+}
+
+// createRoutesSequentially issues one CreateRoute per option, stopping at the
+// first failure.
+func createRoutesSequentially(c *gophercloud.ServiceClient, routingtableID string, opts []CreateRouteOpts) ([]Route, error) {
+	created := make([]Route, 0, len(opts))
+	for _, o := range opts {
+		o.RoutingTableID = routingtableID
+		route, err := CreateRoute(c, o).Extract()
+		if err != nil {
+			return created, err
+		}
+		created = append(created, *route)
+	}
+	return created, nil
+}
+
 // UpdateRouteOptsBuilder allows extensions to add additional parameters to the UpdateRoute request.
 type UpdateRouteOptsBuilder interface {
 	ToRouteUpdateMap() (map[string]interface{}, error)
@@ -307,17 +1091,32 @@ type UpdateRouteOpts struct {
 	// Gateway is the gateway IP for the route
 	Gateway string `json:"gateway,omitempty"`
 	
-	// Description is the description of the route (max 256 bytes)
-	Description string `json:"description,omitempty"`
+	// Description is the description of the route (max 256 bytes).
+	// Leave it nil to keep the current description; point it at an empty
+	// string to clear it.
+	Description *string `json:"description,omitempty"`
 }
 
-// ToRouteUpdateMap builds a request body from UpdateRouteOpts.
+// ToRouteUpdateMap builds a request body from UpdateRouteOpts. It returns a
+// gophercloud.ErrInvalidInput if Description is longer than 256 bytes.
 func (opts UpdateRouteOpts) ToRouteUpdateMap() (map[string]interface{}, error) {
+	if opts.Description != nil {
+		if err := validateDescription("routingtables.UpdateRouteOpts.Description", *opts.Description); err != nil {
+			return nil, err
+		}
+	}
 	return gophercloud.BuildRequestBody(opts, "route")
 }
 
 // UpdateRoute accepts an UpdateRouteOpts struct and updates an existing route using the values provided.
-func UpdateRoute(c *gophercloud.ServiceClient, routeID string, opts UpdateRouteOptsBuilder) (r UpdateRouteResult) {
+func UpdateRoute(c *gophercloud.ServiceClient, routeID string, opts UpdateRouteOptsBuilder, reqOpts ...RequestOption) (r UpdateRouteResult) {
+	return UpdateRouteWithContext(context.Background(), c, routeID, opts, reqOpts...)
+}
+
+// UpdateRouteWithContext is the context-aware variant of UpdateRoute.
+func UpdateRouteWithContext(ctx context.Context, c *gophercloud.ServiceClient, routeID string, opts UpdateRouteOptsBuilder, reqOpts ...RequestOption) (r UpdateRouteResult) {
+	c, done := prepare(ctx, c, "UpdateRoute", reqOpts)
+	defer func() { done(r.Err) }()
 	b, err := opts.ToRouteUpdateMap()
 	if err != nil {
 		r.Err = err
@@ -329,17 +1128,64 @@ func UpdateRoute(c *gophercloud.ServiceClient, routeID string, opts UpdateRouteO
 }
 
 // DeleteRoute accepts a unique ID and deletes the route associated with it.
-func DeleteRoute(c *gophercloud.ServiceClient, routeID string) (r DeleteRouteResult) {
+func DeleteRoute(c *gophercloud.ServiceClient, routeID string, reqOpts ...RequestOption) (r DeleteRouteResult) {
+	return DeleteRouteWithContext(context.Background(), c, routeID, reqOpts...)
+}
+
+// DeleteRouteWithContext is the context-aware variant of DeleteRoute.
+func DeleteRouteWithContext(ctx context.Context, c *gophercloud.ServiceClient, routeID string, reqOpts ...RequestOption) (r DeleteRouteResult) {
+	c, done := prepare(ctx, c, "DeleteRoute", reqOpts)
+	defer func() { done(r.Err) }()
 	resp, err := c.Delete(routeURL(c, routeID), nil)
 	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
 	return
 }
 
+// DeleteRouteIfExists is the same as DeleteRoute, but treats a route that no
+// longer exists (404) as successfully deleted. This makes it safe to call
+// when another actor may have already removed the route.
+func DeleteRouteIfExists(c *gophercloud.ServiceClient, routeID string) (r DeleteRouteResult) {
+	r = DeleteRoute(c, routeID)
+	if _, ok := r.Err.(gophercloud.ErrDefault404); ok {
+		r.Err = nil
+	}
+	return
+}
+
+// DeleteRouteIfNotDefault is the same as DeleteRoute, but first fetches the
+// route and refuses to delete it with an ErrDefaultRoute if it is a
+// system-managed route (see Route.IsDefault).
+func DeleteRouteIfNotDefault(c *gophercloud.ServiceClient, routeID string) (r DeleteRouteResult) {
+	route, err := GetRoute(c, routeID).Extract()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	if route != nil && route.IsDefault() {
+		r.Err = ErrDefaultRoute{RouteID: routeID, CIDR: route.CIDR}
+		return
+	}
+	return DeleteRoute(c, routeID)
+}
+
 // URLs for route operations
 func routesURL(c *gophercloud.ServiceClient) string {
-	return c.ServiceURL("routes")
+	return serviceURL(c, RoutesResourcePath)
 }
 
 func routeURL(c *gophercloud.ServiceClient, id string) string {
-	return c.ServiceURL("routes", id)
+	return serviceURL(c, RoutesResourcePath, id)
+}
+
+// withContext returns a shallow copy of the service client whose requests are
+// issued under ctx. A background context leaves the client untouched, so the
+// provider's own Context (if any) keeps applying.
+func withContext(ctx context.Context, c *gophercloud.ServiceClient) *gophercloud.ServiceClient {
+	if ctx == nil || ctx == context.Background() || ctx == c.Context {
+		return c
+	}
+
+	client := internal.CopyClient(c)
+	client.ProviderClient.Context = ctx
+	return client
 }
@@ -10,17 +10,72 @@ package routingtables
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
-	"time"
+	"unicode"
 
 	"github.com/cloud-barista/nhncloud-sdk-go"
 	"github.com/cloud-barista/nhncloud-sdk-go/pagination"
 )
 
+// RoutingTableState represents possible states of a routing table
+type RoutingTableState string
+
+const (
+	// StateAvailable indicates the routing table is ready for use
+	StateAvailable RoutingTableState = "available"
+	
+	// StatePending indicates a change to the routing table is being applied
+	StatePending RoutingTableState = "pending"
+	
+	// StateError indicates the routing table failed to apply a change
+	StateError RoutingTableState = "error"
+)
+
+// IsTerminal reports whether the state will not change without user action,
+// i.e. whether it is worth polling for a state change.
+func (s RoutingTableState) IsTerminal() bool {
+	return s == StateAvailable || s == StateError
+}
+
+// MarshalMode controls how FlexibleSubnetInfo and FlexibleVPCInfo are
+// marshaled to JSON.
+type MarshalMode int
+
+const (
+	// MarshalAuto marshals to a bare ID string when only the ID is set, and to
+	// an object otherwise.
+	MarshalAuto MarshalMode = iota
+
+	// MarshalAsID always marshals to a bare ID string.
+	MarshalAsID
+
+	// MarshalAsObject always marshals to an object with id and name.
+	MarshalAsObject
+)
+
 // FlexibleSubnetInfo handles both string IDs and full subnet objects from the API
 type FlexibleSubnetInfo struct {
 	ID   string `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
+
+	// MarshalMode selects the JSON output format. It is not decoded from or
+	// encoded to JSON.
+	MarshalMode MarshalMode `json:"-"`
+}
+
+// AsID returns a copy of fsi that always marshals to a bare ID string.
+func (fsi FlexibleSubnetInfo) AsID() FlexibleSubnetInfo {
+	fsi.MarshalMode = MarshalAsID
+	return fsi
+}
+
+// AsObject returns a copy of fsi that always marshals to an object.
+func (fsi FlexibleSubnetInfo) AsObject() FlexibleSubnetInfo {
+	fsi.MarshalMode = MarshalAsObject
+	return fsi
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling to handle both string and object formats
@@ -39,11 +94,16 @@ func (fsi *FlexibleSubnetInfo) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, aux)
 }
 
-// MarshalJSON implements custom JSON marshaling
+// MarshalJSON implements custom JSON marshaling according to MarshalMode
 func (fsi FlexibleSubnetInfo) MarshalJSON() ([]byte, error) {
-	// If only ID is set, marshal as string
-	if fsi.Name == "" && fsi.ID != "" {
+	switch fsi.MarshalMode {
+	case MarshalAsID:
 		return json.Marshal(fsi.ID)
+	case MarshalAuto:
+		// If only ID is set, marshal as string
+		if fsi.Name == "" && fsi.ID != "" {
+			return json.Marshal(fsi.ID)
+		}
 	}
 	
 	// Otherwise marshal as object
@@ -55,6 +115,22 @@ func (fsi FlexibleSubnetInfo) MarshalJSON() ([]byte, error) {
 type FlexibleVPCInfo struct {
 	ID   string `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
+
+	// MarshalMode selects the JSON output format. It is not decoded from or
+	// encoded to JSON.
+	MarshalMode MarshalMode `json:"-"`
+}
+
+// AsID returns a copy of fvi that always marshals to a bare ID string.
+func (fvi FlexibleVPCInfo) AsID() FlexibleVPCInfo {
+	fvi.MarshalMode = MarshalAsID
+	return fvi
+}
+
+// AsObject returns a copy of fvi that always marshals to an object.
+func (fvi FlexibleVPCInfo) AsObject() FlexibleVPCInfo {
+	fvi.MarshalMode = MarshalAsObject
+	return fvi
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling to handle both string and object formats
@@ -73,11 +149,16 @@ func (fvi *FlexibleVPCInfo) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, aux)
 }
 
-// MarshalJSON implements custom JSON marshaling
+// MarshalJSON implements custom JSON marshaling according to MarshalMode
 func (fvi FlexibleVPCInfo) MarshalJSON() ([]byte, error) {
-	// If only ID is set, marshal as string
-	if fvi.Name == "" && fvi.ID != "" {
+	switch fvi.MarshalMode {
+	case MarshalAsID:
 		return json.Marshal(fvi.ID)
+	case MarshalAuto:
+		// If only ID is set, marshal as string
+		if fvi.Name == "" && fvi.ID != "" {
+			return json.Marshal(fvi.ID)
+		}
 	}
 	
 	// Otherwise marshal as object
@@ -85,58 +166,10 @@ func (fvi FlexibleVPCInfo) MarshalJSON() ([]byte, error) {
 	return json.Marshal((vpcAlias)(fvi))
 }
 
-// NHNCloudTime handles the custom timestamp format used by NHN Cloud API
-// Format: "2024-02-13 10:45:57" instead of standard RFC3339
-type NHNCloudTime struct {
-	time.Time
-}
-
-// UnmarshalJSON implements custom JSON unmarshaling for NHN Cloud timestamp format
-func (ct *NHNCloudTime) UnmarshalJSON(data []byte) error {
-	// Remove quotes from JSON string
-	s := strings.Trim(string(data), `"`)
-	
-	// Handle empty/null values
-	if s == "null" || s == "" {
-		return nil
-	}
-	
-	// List of possible time formats used by NHN Cloud API
-	formats := []string{
-		"2006-01-02 15:04:05",           // Most common format
-		"2006-01-02T15:04:05",           // Alternative format without timezone
-		"2006-01-02T15:04:05Z",          // UTC format
-		"2006-01-02T15:04:05Z07:00",     // Full RFC3339
-		"2006-01-02 15:04:05.000000",    // With microseconds
-		"2006-01-02T15:04:05.000000Z",   // RFC3339 with microseconds
-	}
-	
-	var parseErr error
-	for _, format := range formats {
-		if t, err := time.Parse(format, s); err == nil {
-			ct.Time = t
-			return nil
-		} else {
-			parseErr = err
-		}
-	}
-	
-	return fmt.Errorf("unable to parse time %q with any known format: %v", s, parseErr)
-}
-
-// MarshalJSON implements custom JSON marshaling for NHN Cloud timestamp format
-func (ct NHNCloudTime) MarshalJSON() ([]byte, error) {
-	if ct.Time.IsZero() {
-		return []byte("null"), nil
-	}
-	// Format to match NHN Cloud API format
-	return json.Marshal(ct.Time.Format("2006-01-02 15:04:05"))
-}
-
-// String returns string representation of the time
-func (ct NHNCloudTime) String() string {
-	return ct.Time.Format("2006-01-02 15:04:05")
-}
+// NHNCloudTime handles the custom timestamp format used by NHN Cloud API.
+// It is an alias of gophercloud.NHNCloudTime, shared with the other NHN Cloud
+// resource packages.
+type NHNCloudTime = gophercloud.NHNCloudTime
 
 // Helper methods for FlexibleSubnetInfo
 
@@ -182,6 +215,22 @@ func (rt *RoutingTable) GetVPCNames() []string {
 	return names
 }
 
+// Helper methods for counts
+
+// RouteCount returns the number of routes in the routing table. The API
+// reports no separate count, and routes are only included by Get, so the
+// count is 0 for routing tables obtained from List.
+func (rt *RoutingTable) RouteCount() int {
+	return len(rt.Routes)
+}
+
+// SubnetCount returns the number of subnets connected to the routing table.
+// The API reports no separate count, and subnets are only included in the
+// detailed view, so the count is 0 for routing tables listed without Detail.
+func (rt *RoutingTable) SubnetCount() int {
+	return len(rt.Subnets)
+}
+
 // RoutingTable represents a routing table resource.
 type RoutingTable struct {
 	// ID is the unique identifier of the routing table
@@ -205,12 +254,18 @@ type RoutingTable struct {
 	// TenantID is the ID of the tenant that owns the routing table
 	TenantID string `json:"tenant_id"`
 	
-	// State is the current state of the routing table
+	// State is the current state of the routing table (see RoutingTableState)
 	State string `json:"state"`
 	
-	// CreateTime is when the routing table was created
+	// CreateTime is when the routing table was created. Timestamps without
+	// an offset are read as KST, see NHNCloudTime; releases before the type
+	// was shared with internetgateways read them as UTC, 9 hours later.
 	CreateTime NHNCloudTime `json:"create_time"`
 	
+	// UpdateTime is when the routing table was last modified (zero if the API
+	// omits it). It is read like CreateTime.
+	UpdateTime NHNCloudTime `json:"update_time"`
+	
 	// VPCs is a list of VPCs this routing table belongs to (detailed view only)
 	VPCs []FlexibleVPCInfo `json:"vpcs,omitempty"`
 	
@@ -221,6 +276,23 @@ type RoutingTable struct {
 	Routes []Route `json:"routes,omitempty"`
 }
 
+// String returns a one-line summary of the routing table for logs, e.g.
+//
+//	RoutingTable{ID: 6e7e…, Name: "web-rt", State: available, Default: false, Routes: 3}
+//
+// Routes only counts the routes embedded in the routing table, which the API
+// returns for Get only.
+func (rt RoutingTable) String() string {
+	return fmt.Sprintf("RoutingTable{ID: %s, Name: %q, State: %s, Default: %t, Routes: %d}",
+		rt.ID, rt.Name, rt.State, rt.DefaultTable, len(rt.Routes))
+}
+
+// GoString returns the same summary as String, so that %#v does not dump the
+// raw fields.
+func (rt RoutingTable) GoString() string {
+	return rt.String()
+}
+
 // VPCInfo represents VPC information within a routing table (legacy - kept for compatibility).
 type VPCInfo struct {
 	// ID is the VPC ID
@@ -247,7 +319,8 @@ type Route struct {
 	// CIDR is the destination CIDR
 	CIDR string `json:"cidr"`
 	
-	// Mask is the netmask of the destination CIDR
+	// Mask is the prefix length of the destination CIDR (0-32 for IPv4,
+	// 0-128 for IPv6)
 	Mask int `json:"mask"`
 	
 	// Gateway is the gateway IP address
@@ -269,12 +342,101 @@ type Route struct {
 	Hidden bool `json:"hidden,omitempty"`
 }
 
+// String returns a one-line summary of the route for logs, e.g.
+//
+//	Route{ID: 3c1d…, CIDR: 192.168.10.0/24, Gateway: 10.0.0.10, RoutingTable: 6e7e…, Description: "office"}
+//
+// A nil Description is rendered as <nil>.
+func (r Route) String() string {
+	description := "<nil>"
+	if r.Description != nil {
+		description = strconv.Quote(*r.Description)
+	}
+	return fmt.Sprintf("Route{ID: %s, CIDR: %s, Gateway: %s, RoutingTable: %s, Description: %s}",
+		r.ID, r.CIDR, r.Gateway, r.RoutingTableID, description)
+}
+
+// GoString returns the same summary as String, so that %#v does not dump the
+// Description pointer.
+func (r Route) GoString() string {
+	return r.String()
+}
+
+// IsDefault reports whether the route is managed by the system and must not be
+// deleted manually: either a hidden route, or the default route (0.0.0.0/0 or
+// ::/0) pointing at an internet gateway that is created when a gateway is
+// attached to the routing table.
+func (r Route) IsDefault() bool {
+	if r.Hidden {
+		return true
+	}
+	if r.GatewayID == "" {
+		return false
+	}
+	switch r.CIDR {
+	case "0.0.0.0/0", "::/0":
+		return true
+	case "0.0.0.0", "::":
+		return r.Mask == 0
+	}
+	return false
+}
+
+// NormalizedCIDR reconciles the CIDR and Mask fields of the route and returns
+// the destination in canonical CIDR notation. The prefix length in CIDR takes
+// precedence and is copied into Mask if Mask is zero; a bare address in CIDR
+// is combined with Mask. An error is returned if the two disagree or neither
+// carries a usable prefix length.
+func (r *Route) NormalizedCIDR() (string, error) {
+	if strings.Contains(r.CIDR, "/") {
+		_, ipNet, err := net.ParseCIDR(r.CIDR)
+		if err != nil {
+			return "", fmt.Errorf("route [%s] has an invalid CIDR %q: %w", r.ID, r.CIDR, err)
+		}
+		prefix, _ := ipNet.Mask.Size()
+		if r.Mask != 0 && r.Mask != prefix {
+			return "", fmt.Errorf("route [%s] CIDR %q conflicts with mask %d", r.ID, r.CIDR, r.Mask)
+		}
+		r.Mask = prefix
+		return ipNet.String(), nil
+	}
+
+	ip := net.ParseIP(r.CIDR)
+	if ip == nil {
+		return "", fmt.Errorf("route [%s] has an invalid CIDR %q", r.ID, r.CIDR)
+	}
+	bits := 128
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 32
+	}
+	if r.Mask < 0 || r.Mask > bits || (r.Mask == 0 && !ip.IsUnspecified()) {
+		return "", fmt.Errorf("route [%s] address %q has no usable mask (%d)", r.ID, r.CIDR, r.Mask)
+	}
+	ipNet := net.IPNet{IP: ip.Mask(net.CIDRMask(r.Mask, bits)), Mask: net.CIDRMask(r.Mask, bits)}
+	return ipNet.String(), nil
+}
+
+// RouteWithTable is a route annotated with the name of its routing table, as
+// returned by ListRoutesWithTableNames.
+type RouteWithTable struct {
+	Route
+
+	// RoutingTableName is the name of the routing table of the route, or
+	// empty if the routing table could not be found
+	RoutingTableName string `json:"routingtable_name"`
+}
+
+// GatewayTypeInternetGateway is the Type of an internet gateway reachable
+// through a routing table. The API may report other gateway types, which can
+// be filtered on with FilterGatewaysByType as well.
+const GatewayTypeInternetGateway = "internetgateway"
+
 // Gateway represents a gateway that can be reached through routing policies.
 type Gateway struct {
 	// ID is the gateway ID
 	ID string `json:"id"`
 	
-	// Type is the gateway type
+	// Type is the gateway type, e.g. GatewayTypeInternetGateway
 	Type string `json:"type"`
 	
 	// Name is the gateway name
@@ -288,15 +450,91 @@ type RoutingTablePage struct {
 
 // NextPageURL is invoked when a paginated collection of routing tables has reached the end of a page
 // and the pager seeks to traverse over a new one.
+//
+// Links that cannot be followed are reported as ErrMalformedPageLinks, so that
+// AllPages and EachPage fail instead of silently stopping after this page.
 func (r RoutingTablePage) NextPageURL() (string, error) {
-	var s struct {
-		Links []gophercloud.Link `json:"routingtables_links"`
-	}
-	err := r.ExtractInto(&s)
+	links, err := pageLinks(r.PageResult, "routingtables_links")
 	if err != nil {
 		return "", err
 	}
-	return gophercloud.ExtractNextURL(s.Links)
+	return nextPageURL(r.URL, links)
+}
+
+// pageLinks extracts the links stored under key from a page body. A
+// misspelling of key, such as "routingtable_links" for "routingtables_links",
+// is reported rather than taken for the absence of further pages. Unrelated
+// links elements, such as "subnet_links", are ignored.
+func pageLinks(page pagination.PageResult, key string) ([]gophercloud.Link, error) {
+	body, _ := page.Body.(map[string]interface{})
+	for k := range body {
+		if k != key && strings.HasSuffix(strings.ToLower(k), "links") && linksKeyName(k) == linksKeyName(key) {
+			return nil, ErrMalformedPageLinks{Key: k, Reason: fmt.Sprintf("expected the links under %q", key)}
+		}
+	}
+
+	raw, ok := body[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, ErrMalformedPageLinks{Key: key, Err: err}
+	}
+	var links []gophercloud.Link
+	if err := json.Unmarshal(b, &links); err != nil {
+		return nil, ErrMalformedPageLinks{Key: key, Err: err}
+	}
+	return links, nil
+}
+
+// linksKeyName reduces a links key to the resource it names, ignoring case,
+// separators and plurals, so that "routing_table-links" and
+// "routingtables_links" both give "routingtable".
+func linksKeyName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+	name = strings.TrimSuffix(name, "links")
+	return strings.TrimSuffix(name, "s")
+}
+
+// nextPageURL extracts the next page URL from the page links. The API does
+// not always echo the limit query parameter in the links, so the limit of the
+// current page is carried over to keep the requested page size.
+func nextPageURL(current url.URL, links []gophercloud.Link) (string, error) {
+	for _, l := range links {
+		if l.Rel == "next" && l.Href == "" {
+			return "", ErrMalformedPageLinks{Reason: "the next link has no href"}
+		}
+	}
+	next, err := gophercloud.ExtractNextURL(links)
+	if err != nil || next == "" {
+		return next, err
+	}
+
+	u, err := url.Parse(next)
+	if err != nil {
+		return "", ErrMalformedPageLinks{Href: next, Err: err}
+	}
+	if !u.IsAbs() {
+		return "", ErrMalformedPageLinks{Href: next, Reason: "the next link is not an absolute URL"}
+	}
+
+	limit := current.Query().Get("limit")
+	if limit == "" {
+		return next, nil
+	}
+
+	q := u.Query()
+	if q.Get("limit") == "" {
+		q.Set("limit", limit)
+		u.RawQuery = q.Encode()
+	}
+	return u.String(), nil
 }
 
 // IsEmpty checks whether a RoutingTablePage struct is empty.
@@ -305,14 +543,38 @@ func (r RoutingTablePage) IsEmpty() (bool, error) {
 	return len(is) == 0, err
 }
 
+// TotalCount returns the size of the whole routing table collection, as
+// reported by the X-Total-Count header of the page. ok is false if the
+// response has no such header or if it is not a count.
+func (r RoutingTablePage) TotalCount() (count int, ok bool) {
+	return totalCount(r.PageResult)
+}
+
+// totalCount reads the X-Total-Count header of a page.
+func totalCount(page pagination.PageResult) (int, bool) {
+	count, err := strconv.Atoi(page.Header.Get("X-Total-Count"))
+	if err != nil || count < 0 {
+		return 0, false
+	}
+	return count, true
+}
+
 // ExtractRoutingTables accepts a Page struct, specifically a RoutingTablePage struct,
-// and extracts the elements into a slice of RoutingTable structs.
+// and extracts the elements into a slice of RoutingTable structs. The slice is
+// empty but never nil if the page holds no routing table.
 func ExtractRoutingTables(r pagination.Page) ([]RoutingTable, error) {
-	var s struct {
-		RoutingTables []RoutingTable `json:"routingtables"`
+	var s []RoutingTable
+	err := ExtractRoutingTablesInto(r, &s)
+	if err == nil && s == nil {
+		s = []RoutingTable{}
 	}
-	err := (r.(RoutingTablePage)).ExtractInto(&s)
-	return s.RoutingTables, err
+	return s, err
+}
+
+// ExtractRoutingTablesInto extracts the routing tables of a RoutingTablePage
+// into v, which must be a pointer to a slice of a caller-defined type.
+func ExtractRoutingTablesInto(r pagination.Page, v interface{}) error {
+	return r.(RoutingTablePage).Result.ExtractIntoSlicePtr(v, "routingtables")
 }
 
 // RoutePage is the page returned by a pager when traversing over a collection of routes.
@@ -321,16 +583,21 @@ type RoutePage struct {
 }
 
 // NextPageURL is invoked when a paginated collection of routes has reached the end of a page
-// and the pager seeks to traverse over a new one.
+// and the pager seeks to traverse over a new one. Links that cannot be
+// followed are reported as ErrMalformedPageLinks.
 func (r RoutePage) NextPageURL() (string, error) {
-	var s struct {
-		Links []gophercloud.Link `json:"routes_links"`
-	}
-	err := r.ExtractInto(&s)
+	links, err := pageLinks(r.PageResult, "routes_links")
 	if err != nil {
 		return "", err
 	}
-	return gophercloud.ExtractNextURL(s.Links)
+	return nextPageURL(r.URL, links)
+}
+
+// TotalCount returns the size of the whole route collection, as reported by
+// the X-Total-Count header of the page. ok is false if the response has no
+// such header or if it is not a count.
+func (r RoutePage) TotalCount() (count int, ok bool) {
+	return totalCount(r.PageResult)
 }
 
 // IsEmpty checks whether a RoutePage struct is empty.
@@ -340,22 +607,47 @@ func (r RoutePage) IsEmpty() (bool, error) {
 }
 
 // ExtractRoutes accepts a Page struct, specifically a RoutePage struct,
-// and extracts the elements into a slice of Route structs.
+// and extracts the elements into a slice of Route structs. The slice is empty
+// but never nil if the page holds no route.
 func ExtractRoutes(r pagination.Page) ([]Route, error) {
-	var s struct {
-		Routes []Route `json:"routes"`
+	var s []Route
+	err := ExtractRoutesInto(r, &s)
+	if err == nil && s == nil {
+		s = []Route{}
 	}
-	err := (r.(RoutePage)).ExtractInto(&s)
-	return s.Routes, err
+	return s, err
+}
+
+// ExtractRoutesInto extracts the routes of a RoutePage into v, which must be
+// a pointer to a slice of a caller-defined type.
+func ExtractRoutesInto(r pagination.Page, v interface{}) error {
+	return r.(RoutePage).Result.ExtractIntoSlicePtr(v, "routes")
 }
 
+// StrictExtract disables the lenient parsing of RoutingTableResult.Extract
+// package-wide, as if every call were ExtractStrict. It is meant for tests
+// and should not be changed while requests are in flight.
+var StrictExtract = false
+
 // RoutingTableResult represents the result of routing table operations.
 type RoutingTableResult struct {
 	gophercloud.Result
+
+	// raw is the response body as received, see RawBody.
+	raw []byte
 }
 
 // Extract is a function that accepts a result and extracts a routing table resource.
+//
+// If the response does not match the RoutingTable schema, Extract falls back
+// to a field-by-field parse that keeps whatever it can read and drops the
+// rest. This keeps callers working across minor API changes, at the cost of
+// possibly returning partial data without an error. Use ExtractStrict, or set
+// StrictExtract, to get the unmarshal error instead.
 func (r RoutingTableResult) Extract() (*RoutingTable, error) {
+	if StrictExtract {
+		return r.ExtractStrict()
+	}
 	if r.Err != nil {
 		return nil, r.Err
 	}
@@ -374,6 +666,31 @@ func (r RoutingTableResult) Extract() (*RoutingTable, error) {
 	return s.RoutingTable, nil
 }
 
+// ExtractStrict is like Extract, but does not fall back to lenient parsing:
+// any mismatch between the response and the RoutingTable schema is returned
+// as the original unmarshal error. It is meant for tests that need to catch
+// API changes early.
+func (r RoutingTableResult) ExtractStrict() (*RoutingTable, error) {
+	var s struct {
+		RoutingTable *RoutingTable `json:"routingtable"`
+	}
+	err := r.ExtractInto(&s)
+	if err != nil {
+		return nil, err
+	}
+	return s.RoutingTable, nil
+}
+
+// RawBody returns the response body as it was received, regardless of
+// whether Extract succeeds through the standard or the fallback path. It is
+// meant for debugging the parsing of unexpected responses. When the response
+// did not hold the routing table and it was fetched again, RawBody returns
+// the body of that second response. RawBody returns nil if the response had
+// no body, or if the result was not produced by a request.
+func (r RoutingTableResult) RawBody() ([]byte, error) {
+	return r.raw, nil
+}
+
 // ExtractRoutingTableWithFallback provides fallback parsing for different API response formats
 func (r RoutingTableResult) ExtractRoutingTableWithFallback() (*RoutingTable, error) {
 	if r.Err != nil {
@@ -396,6 +713,9 @@ func (r RoutingTableResult) ExtractRoutingTableWithFallback() (*RoutingTable, er
 	if err != nil {
 		// If that fails, try parsing with raw map to debug
 		var rawRT map[string]interface{}
+		if StrictExtract {
+			return nil, fmt.Errorf("failed to unmarshal routing table: %w", err)
+		}
 		if jsonErr := json.Unmarshal(response.RoutingTable, &rawRT); jsonErr == nil {
 			return r.parseRoutingTableFromMap(rawRT)
 		}
@@ -443,6 +763,14 @@ func (r RoutingTableResult) parseRoutingTableFromMap(data map[string]interface{}
 		}
 	}
 	
+	// Parse update_time
+	if updateTimeStr, ok := data["update_time"].(string); ok {
+		var ut NHNCloudTime
+		if err := json.Unmarshal([]byte(`"`+updateTimeStr+`"`), &ut); err == nil {
+			rt.UpdateTime = ut
+		}
+	}
+	
 	// Parse VPCs (handle both string array and object array)
 	if vpcs, ok := data["vpcs"]; ok {
 		rt.VPCs = r.parseFlexibleVPCs(vpcs)
@@ -461,9 +789,10 @@ func (r RoutingTableResult) parseRoutingTableFromMap(data map[string]interface{}
 	return rt, nil
 }
 
-// parseFlexibleVPCs handles both string arrays and object arrays for VPCs
+// parseFlexibleVPCs handles both string arrays and object arrays for VPCs.
+// The slice is empty but never nil, as with the standard unmarshal path.
 func (r RoutingTableResult) parseFlexibleVPCs(vpcs interface{}) []FlexibleVPCInfo {
-	var result []FlexibleVPCInfo
+	result := make([]FlexibleVPCInfo, 0)
 	
 	switch v := vpcs.(type) {
 	case []interface{}:
@@ -494,9 +823,10 @@ func (r RoutingTableResult) parseFlexibleVPCs(vpcs interface{}) []FlexibleVPCInf
 	return result
 }
 
-// parseFlexibleSubnets handles both string arrays and object arrays for Subnets
+// parseFlexibleSubnets handles both string arrays and object arrays for
+// Subnets. The slice is empty but never nil.
 func (r RoutingTableResult) parseFlexibleSubnets(subnets interface{}) []FlexibleSubnetInfo {
-	var result []FlexibleSubnetInfo
+	result := make([]FlexibleSubnetInfo, 0)
 	
 	switch v := subnets.(type) {
 	case []interface{}:
@@ -527,9 +857,10 @@ func (r RoutingTableResult) parseFlexibleSubnets(subnets interface{}) []Flexible
 	return result
 }
 
-// parseRoutes handles route parsing from interface{} array
+// parseRoutes handles route parsing from interface{} array. The slice is
+// empty but never nil.
 func (r RoutingTableResult) parseRoutes(routes []interface{}) []Route {
-	var result []Route
+	result := make([]Route, 0)
 	
 	for _, route := range routes {
 		if routeMap, ok := route.(map[string]interface{}); ok {
@@ -629,11 +960,15 @@ type GatewayResult struct {
 }
 
 // Extract is a function that accepts a result and extracts gateway resources.
+// A routing table without gateways yields an empty, non-nil slice.
 func (r GatewayResult) Extract() ([]Gateway, error) {
 	var s struct {
 		Gateways []Gateway `json:"gateways"`
 	}
 	err := r.ExtractInto(&s)
+	if err == nil && s.Gateways == nil {
+		s.Gateways = []Gateway{}
+	}
 	return s.Gateways, err
 }
 
@@ -674,6 +1009,16 @@ type SetAsDefaultResult struct {
 	RoutingTableResult
 }
 
+// AssociateSubnetResult represents the result of an associate subnet operation.
+type AssociateSubnetResult struct {
+	RoutingTableResult
+}
+
+// DisassociateSubnetResult represents the result of a disassociate subnet operation.
+type DisassociateSubnetResult struct {
+	RoutingTableResult
+}
+
 // GetRelatedGatewaysResult represents the result of a get related gateways operation.
 type GetRelatedGatewaysResult struct {
 	GatewayResult
@@ -696,6 +1041,23 @@ type UpdateRouteResult struct {
 	RouteResult
 }
 
+// BulkCreateRoutesResult represents the result of a bulk create routes operation.
+type BulkCreateRoutesResult struct {
+	gophercloud.Result
+}
+
+// Extract is a function that accepts a result and extracts the created route resources.
+func (r BulkCreateRoutesResult) Extract() ([]Route, error) {
+	var s struct {
+		Routes []Route `json:"routes"`
+	}
+	err := r.ExtractInto(&s)
+	if err == nil && s.Routes == nil {
+		s.Routes = []Route{}
+	}
+	return s.Routes, err
+}
+
 // DeleteRouteResult represents the result of a delete route operation.
 type DeleteRouteResult struct {
 	gophercloud.ErrResult
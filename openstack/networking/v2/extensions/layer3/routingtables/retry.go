@@ -0,0 +1,155 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package routingtables
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
+
+// RetryPolicy configures the retries of requests that failed with a transient
+// server error. See WithRetryPolicy.
+type RetryPolicy struct {
+	// StatusCodes are the response codes that are retried. Defaults to 500,
+	// 502 and 503.
+	StatusCodes []int
+
+	// InitialBackoff is the delay before the first retry. It doubles after
+	// each retry. Defaults to 500 milliseconds.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts. Defaults to 10 seconds.
+	MaxBackoff time.Duration
+
+	// MaxElapsedTime bounds the total time spent on a request, retries
+	// included; no retry is attempted past it. Defaults to 1 minute.
+	MaxElapsedTime time.Duration
+
+	// RetryNonIdempotent also retries POST, PUT, PATCH and DELETE requests.
+	// A failed response does not guarantee that the server did not apply
+	// the request, so this may, for instance, create a resource twice.
+	RetryNonIdempotent bool
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if len(p.StatusCodes) == 0 {
+		p.StatusCodes = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 500 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 10 * time.Second
+	}
+	if p.MaxElapsedTime <= 0 {
+		p.MaxElapsedTime = 1 * time.Minute
+	}
+	return p
+}
+
+// WithRetryPolicy returns a copy of the service client whose requests are
+// retried according to the policy when they fail with a transient server
+// error. Only GET and HEAD requests, including those of List pagers, are
+// retried unless RetryNonIdempotent is set.
+//
+// The delay before a retry is the exponential backoff with jitter, but at
+// least the time the server took to answer and any Retry-After it sent, so
+// that a struggling server is given more room.
+func WithRetryPolicy(c *gophercloud.ServiceClient, policy RetryPolicy) *gophercloud.ServiceClient {
+	client := internal.CopyClient(c)
+	next := client.ProviderClient.HTTPClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.ProviderClient.HTTPClient.Transport = &retryTransport{next: next, policy: policy.withDefaults()}
+	return client
+}
+
+// retryTransport is an http.RoundTripper retrying requests according to a
+// RetryPolicy.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.retryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	backoff := t.policy.InitialBackoff
+	for {
+		attempt := req
+		if req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt = req.Clone(req.Context())
+			attempt.Body = body
+		}
+
+		sent := time.Now()
+		resp, err := t.next.RoundTrip(attempt)
+		if err != nil || !t.retryStatus(resp.StatusCode) {
+			return resp, err
+		}
+
+		delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		if took := time.Since(sent); took > delay {
+			delay = took
+		}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && time.Duration(seconds)*time.Second > delay {
+			delay = time.Duration(seconds) * time.Second
+		}
+		if time.Since(start)+delay > t.policy.MaxElapsedTime {
+			return resp, nil
+		}
+
+		// Drain the body so the connection can be reused.
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > t.policy.MaxBackoff {
+			backoff = t.policy.MaxBackoff
+		}
+	}
+}
+
+// retryable reports whether the request may be sent again.
+func (t *retryTransport) retryable(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+	return t.policy.RetryNonIdempotent && (req.Body == nil || req.GetBody != nil)
+}
+
+func (t *retryTransport) retryStatus(code int) bool {
+	for _, c := range t.policy.StatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
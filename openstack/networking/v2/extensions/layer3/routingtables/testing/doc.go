@@ -0,0 +1,2 @@
+// routingtables unit tests
+package testing
@@ -0,0 +1,487 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	fake "github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/common"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/routingtables"
+	th "github.com/cloud-barista/nhncloud-sdk-go/testhelper"
+)
+
+// RoutingTableID is the ID of the routing table used across the fixtures.
+const RoutingTableID = "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"
+
+// GetResponseTemplate is a Get response for a routing table whose state is
+// substituted by the caller.
+const GetResponseTemplate = `
+{
+    "routingtable": {
+        "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+        "name": "rt-web",
+        "default_table": false,
+        "distributed": true,
+        "gateway_id": "",
+        "gateway_name": "",
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "state": "%s",
+        "create_time": "2024-02-13 10:45:57",
+        "update_time": "2024-03-02 16:20:11",
+        "vpcs": [
+            {
+                "id": "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c",
+                "name": "vpc-web"
+            }
+        ],
+        "subnets": [],
+        "routes": [
+            {
+                "id": "0f3e1c2a-5b7d-4e9f-8a6c-1d2e3f4a5b6c",
+                "cidr": "10.0.0.0/16",
+                "mask": 16,
+                "gateway": "10.0.0.1",
+                "description": null,
+                "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+                "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+                "hidden": true
+            }
+        ]
+    }
+}
+`
+
+// HandleGetStatesSuccessfully registers a Get handler that reports the given
+// states in order, repeating the last one once they are exhausted.
+func HandleGetStatesSuccessfully(t *testing.T, states ...string) *int {
+	calls := 0
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+
+		state := states[len(states)-1]
+		if calls < len(states) {
+			state = states[calls]
+		}
+		calls++
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, GetResponseTemplate, state)
+	})
+	return &calls
+}
+
+// AssociateSubnetRequest is the expected body of an AssociateSubnet request.
+const AssociateSubnetRequest = `
+{
+    "subnet_id": "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"
+}
+`
+
+// AssociateSubnetResponse is the response to an AssociateSubnet request.
+const AssociateSubnetResponse = `
+{
+    "routingtable": {
+        "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+        "name": "rt-web",
+        "default_table": false,
+        "distributed": true,
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "state": "available",
+        "create_time": "2024-02-13 10:45:57",
+        "vpcs": ["a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"],
+        "subnets": ["c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"]
+    }
+}
+`
+
+// BulkCreateRoutesRequest is the expected body of a BulkCreateRoutes request.
+const BulkCreateRoutesRequest = `
+{
+    "routes": [
+        {
+            "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "cidr": "192.168.10.0/24",
+            "gateway": "10.0.0.10",
+            "description": "office"
+        },
+        {
+            "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "cidr": "192.168.20.0/24",
+            "gateway": "10.0.0.20",
+            "description": "lab"
+        }
+    ]
+}
+`
+
+// BulkCreateRoutesResponse is the response to a BulkCreateRoutes request.
+const BulkCreateRoutesResponse = `
+{
+    "routes": [
+        {
+            "id": "3a4b5c6d-7e8f-4a9b-8c0d-1e2f3a4b5c6d",
+            "cidr": "192.168.10.0/24",
+            "mask": 24,
+            "gateway": "10.0.0.10",
+            "description": "office",
+            "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f"
+        },
+        {
+            "id": "4b5c6d7e-8f9a-4b0c-9d1e-2f3a4b5c6d7e",
+            "cidr": "192.168.20.0/24",
+            "mask": 24,
+            "gateway": "10.0.0.20",
+            "description": "lab",
+            "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f"
+        }
+    ]
+}
+`
+
+// CreateRouteResponseTemplate is a CreateRoute response whose ID, CIDR and
+// gateway are substituted by the caller.
+const CreateRouteResponseTemplate = `
+{
+    "route": {
+        "id": "%s",
+        "cidr": "%s",
+        "mask": 24,
+        "gateway": "%s",
+        "description": null,
+        "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f"
+    }
+}
+`
+
+// BulkCreateRoutesOpts are the options matching BulkCreateRoutesRequest.
+var BulkCreateRoutesOpts = []routingtables.CreateRouteOpts{
+	{CIDR: "192.168.10.0/24", Gateway: "10.0.0.10", Description: "office"},
+	{CIDR: "192.168.20.0/24", Gateway: "10.0.0.20", Description: "lab"},
+}
+
+// ListDefaultResponse is a List response holding the default routing tables
+// of two VPCs.
+const ListDefaultResponse = `
+{
+    "routingtables": [
+        {
+            "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "name": "rt-web",
+            "default_table": true,
+            "distributed": true,
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+            "state": "available",
+            "create_time": "2024-02-13 10:45:57",
+            "vpcs": [{"id": "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", "name": "vpc-web"}]
+        },
+        {
+            "id": "7f8a9b0c-1d2e-4f3a-8b4c-5d6e7f8a9b0c",
+            "name": "rt-db",
+            "default_table": true,
+            "distributed": false,
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+            "state": "available",
+            "create_time": "2024-02-14 08:12:03",
+            "vpcs": ["b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d"]
+        }
+    ]
+}
+`
+
+// ListRoutesPage1 is the first page of a ListRoutes response; the link to the
+// second page is substituted by the caller.
+const ListRoutesPage1 = `
+{
+    "routes": [
+        {
+            "id": "3a4b5c6d-7e8f-4a9b-8c0d-1e2f3a4b5c6d",
+            "cidr": "192.168.10.0/24",
+            "mask": 24,
+            "gateway": "10.0.0.10",
+            "description": "office",
+            "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f"
+        }
+    ],
+    "routes_links": [
+        {
+            "href": "%s",
+            "rel": "next"
+        }
+    ]
+}
+`
+
+// ListRoutesPage2 is the second and last page of a ListRoutes response.
+const ListRoutesPage2 = `
+{
+    "routes": [
+        {
+            "id": "4b5c6d7e-8f9a-4b0c-9d1e-2f3a4b5c6d7e",
+            "cidr": "192.168.20.0/24",
+            "mask": 24,
+            "gateway": "10.0.0.20",
+            "description": "lab",
+            "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f"
+        }
+    ]
+}
+`
+
+// HandleListRoutesSuccessfully registers a two-page ListRoutes handler.
+func HandleListRoutesSuccessfully(t *testing.T) {
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		if r.URL.Query().Get("marker") == "" {
+			fmt.Fprintf(w, ListRoutesPage1, th.Server.URL+"/v2.0/routes?marker=3a4b5c6d-7e8f-4a9b-8c0d-1e2f3a4b5c6d")
+		} else {
+			fmt.Fprint(w, ListRoutesPage2)
+		}
+	})
+}
+
+// ListByNameResponse is a List response holding two routing tables sharing a
+// name within the same VPC, and one in another VPC.
+const ListByNameResponse = `
+{
+    "routingtables": [
+        {
+            "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "name": "rt-shared",
+            "state": "available",
+            "vpcs": [{"id": "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", "name": "vpc-web"}]
+        },
+        {
+            "id": "8a9b0c1d-2e3f-4a4b-9c5d-6e7f8a9b0c1d",
+            "name": "rt-shared",
+            "state": "available",
+            "vpcs": [{"id": "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", "name": "vpc-web"}]
+        },
+        {
+            "id": "7f8a9b0c-1d2e-4f3a-8b4c-5d6e7f8a9b0c",
+            "name": "rt-shared",
+            "state": "available",
+            "vpcs": [{"id": "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d", "name": "vpc-db"}]
+        }
+    ]
+}
+`
+
+// GetWithSubnetsResponse is a Get response for a routing table associated
+// with two subnets.
+const GetWithSubnetsResponse = `
+{
+    "routingtable": {
+        "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+        "name": "rt-web",
+        "default_table": false,
+        "distributed": true,
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "state": "available",
+        "create_time": "2024-02-13 10:45:57",
+        "vpcs": ["a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"],
+        "subnets": [
+            "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f",
+            {
+                "id": "d2e3f4a5-b6c7-4d8e-9f0a-1b2c3d4e5f6a",
+                "name": "subnet-db"
+            }
+        ]
+    }
+}
+`
+
+// SetAsDefaultResponse is the response to a SetAsDefault request, also used
+// as the Get response once the routing table became the default one.
+const SetAsDefaultResponse = `
+{
+    "routingtable": {
+        "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+        "name": "rt-web",
+        "default_table": true,
+        "distributed": true,
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "state": "available",
+        "create_time": "2024-02-13 10:45:57",
+        "vpcs": ["a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"],
+        "subnets": []
+    }
+}
+`
+
+// ListPage1 is the first page of a List response; the link to the second
+// page is substituted by the caller. The second page is ListDefaultResponse.
+const ListPage1 = `
+{
+    "routingtables": [
+        {
+            "id": "8a9b0c1d-2e3f-4a4b-9c5d-6e7f8a9b0c1d",
+            "name": "rt-batch",
+            "default_table": false,
+            "distributed": true,
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+            "state": "available",
+            "create_time": "2024-02-12 21:03:44",
+            "vpcs": ["a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"]
+        }
+    ],
+    "routingtables_links": [
+        {
+            "href": "%s",
+            "rel": "next"
+        }
+    ]
+}
+`
+
+// CloneSourceResponse is a Get response for a routing table holding a hidden
+// route, an internet gateway default route and one user route.
+const CloneSourceResponse = `
+{
+    "routingtable": {
+        "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+        "name": "rt-web",
+        "default_table": false,
+        "distributed": false,
+        "gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e",
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "state": "available",
+        "create_time": "2024-02-13 10:45:57",
+        "vpcs": ["a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"],
+        "subnets": [],
+        "routes": [
+            {
+                "id": "0f3e1c2a-5b7d-4e9f-8a6c-1d2e3f4a5b6c",
+                "cidr": "10.0.0.0/16",
+                "mask": 16,
+                "gateway": "10.0.0.1",
+                "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+                "hidden": true
+            },
+            {
+                "id": "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+                "cidr": "0.0.0.0/0",
+                "mask": 0,
+                "gateway": "",
+                "gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e",
+                "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"
+            },
+            {
+                "id": "3a4b5c6d-7e8f-4a9b-8c0d-1e2f3a4b5c6d",
+                "cidr": "192.168.10.0/24",
+                "mask": 24,
+                "gateway": "10.0.0.10",
+                "description": "office",
+                "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"
+            }
+        ]
+    }
+}
+`
+
+// CloneRequest is the expected body of the Create request issued by
+// CloneRoutingTable.
+const CloneRequest = `
+{
+    "routingtable": {
+        "name": "rt-web-copy",
+        "vpc_id": "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d",
+        "distributed": false
+    }
+}
+`
+
+// CloneResponse is the response to CloneRequest, also used as the Get
+// response of the cloned routing table.
+const CloneResponse = `
+{
+    "routingtable": {
+        "id": "9c0d1e2f-3a4b-4c5d-8e6f-7a8b9c0d1e2f",
+        "name": "rt-web-copy",
+        "default_table": false,
+        "distributed": false,
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "state": "available",
+        "create_time": "2024-03-04 09:00:00",
+        "vpcs": ["b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d"],
+        "subnets": []
+    }
+}
+`
+
+// GetInternetGatewayResponseTemplate is an internet gateway Get response
+// whose state is substituted by the caller.
+const GetInternetGatewayResponseTemplate = `
+{
+    "internetgateway": {
+        "id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e",
+        "name": "igw-web",
+        "external_network_id": "751b8227-7b6a-4b3c-9d2e-1f0a2b3c4d5e",
+        "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+        "state": "%s",
+        "create_time": "2024-02-13 10:45:57",
+        "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+        "migrate_status": "none",
+        "migrate_error": null
+    }
+}
+`
+
+// ListDetailResponse is a detailed List response in which one of the
+// subnets is still returned as a bare ID.
+const ListDetailResponse = `
+{
+    "routingtables": [
+        {
+            "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+            "name": "rt-web",
+            "default_table": false,
+            "distributed": true,
+            "tenant_id": "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f",
+            "state": "available",
+            "create_time": "2024-02-13 10:45:57",
+            "vpcs": ["a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"],
+            "subnets": [
+                "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f",
+                {
+                    "id": "d2e3f4a5-b6c7-4d8e-9f0a-1b2c3d4e5f6a",
+                    "name": "subnet-db"
+                }
+            ]
+        }
+    ]
+}
+`
+
+// ListSubnetsResponse is a vpcsubnets List response used to resolve subnet
+// names.
+const ListSubnetsResponse = `
+{
+    "vpcsubnets": [
+        {
+            "id": "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f",
+            "name": "subnet-web",
+            "vpc_id": "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c",
+            "cidr": "10.0.1.0/24"
+        },
+        {
+            "id": "d2e3f4a5-b6c7-4d8e-9f0a-1b2c3d4e5f6a",
+            "name": "subnet-db",
+            "vpc_id": "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c",
+            "cidr": "10.0.2.0/24"
+        }
+    ]
+}
+`
@@ -0,0 +1,2837 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	fake "github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/common"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/routingtables"
+	"github.com/cloud-barista/nhncloud-sdk-go/pagination"
+	th "github.com/cloud-barista/nhncloud-sdk-go/testhelper"
+)
+
+func TestWaitForState(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	calls := HandleGetStatesSuccessfully(t, "pending", "available")
+
+	err := routingtables.WaitForState(fake.ServiceClient(), RoutingTableID, "available", 10*time.Second)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, *calls)
+}
+
+func TestWaitForStateError(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	calls := HandleGetStatesSuccessfully(t, "error")
+
+	err := routingtables.WaitForState(fake.ServiceClient(), RoutingTableID, "available", 10*time.Second)
+	th.AssertErr(t, err)
+	th.AssertEquals(t, 1, *calls)
+}
+
+func TestWaitForStateTimeout(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	HandleGetStatesSuccessfully(t, "pending")
+
+	err := routingtables.WaitForState(fake.ServiceClient(), RoutingTableID, "available", 0)
+	if _, ok := err.(gophercloud.ErrTimeOut); !ok {
+		t.Fatalf("expected gophercloud.ErrTimeOut, got %#v", err)
+	}
+}
+
+func TestGetWithContextCanceled(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	calls := HandleGetStatesSuccessfully(t, "available")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := routingtables.GetWithContext(ctx, fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertErr(t, err)
+	th.AssertEquals(t, 0, *calls)
+
+	rt, err := routingtables.GetWithContext(context.Background(), fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "available", rt.State)
+	// The fixture's "2024-03-02 16:20:11" has no offset and is read as KST,
+	// not UTC as before NHNCloudTime was shared with internetgateways.
+	th.AssertEquals(t, true, rt.UpdateTime.Equal(time.Date(2024, 3, 2, 7, 20, 11, 0, time.UTC)))
+	th.AssertEquals(t, "2024-03-02 16:20:11", rt.UpdateTime.String())
+}
+
+func TestAssociateSubnet(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/attach_subnet", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		th.TestJSONRequest(t, r, AssociateSubnetRequest)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, AssociateSubnetResponse)
+	})
+
+	rt, err := routingtables.AssociateSubnet(fake.ServiceClient(), RoutingTableID, "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f").Extract()
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, []string{"c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"}, rt.GetSubnetIDs())
+}
+
+func TestBulkCreateRoutes(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		th.TestJSONRequest(t, r, BulkCreateRoutesRequest)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		fmt.Fprint(w, BulkCreateRoutesResponse)
+	})
+
+	routes, err := routingtables.BulkCreateRoutes(fake.ServiceClient(), RoutingTableID, BulkCreateRoutesOpts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(routes))
+	th.AssertEquals(t, "4b5c6d7e-8f9a-4b0c-9d1e-2f3a4b5c6d7e", routes[1].ID)
+}
+
+func TestBulkCreateRoutesFallback(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+
+		var body map[string]map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["route"] == nil {
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"NeutronError": {"type": "HTTPBadRequest", "message": "Unable to find 'route' in request body"}}`)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		route := body["route"]
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "id-"+route["cidr"].(string), route["cidr"], route["gateway"])
+	})
+
+	routes, err := routingtables.BulkCreateRoutes(fake.ServiceClient(), RoutingTableID, BulkCreateRoutesOpts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(routes))
+	th.AssertEquals(t, "id-192.168.20.0/24", routes[1].ID)
+	th.AssertEquals(t, "10.0.0.20", routes[1].Gateway)
+}
+
+func TestBulkCreateRoutesErrors(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	status, body := http.StatusBadRequest, `{"NeutronError": {"type": "HTTPBadRequest", "message": "Invalid input for cidr."}}`
+	posts := 0
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "X-Trace-Id", "trace-42")
+		posts++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	})
+
+	recorder := &hookRecorder{}
+	routingtables.SetHooks(recorder)
+	defer routingtables.SetHooks(nil)
+
+	// A validation error of the batch is not taken for a lack of support.
+	_, err := routingtables.BulkCreateRoutes(fake.ServiceClient(), RoutingTableID, BulkCreateRoutesOpts, routingtables.WithHeader("X-Trace-Id", "trace-42"))
+	if _, ok := err.(gophercloud.ErrDefault400); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault400, got %#v", err)
+	}
+	th.AssertEquals(t, 1, posts)
+	th.AssertDeepEquals(t, []string{"start routingtables.BulkCreateRoutes", "end routingtables.BulkCreateRoutes false"}, recorder.events)
+
+	// Without batch support, each route is created on its own.
+	posts = 0
+	status, body = http.StatusMethodNotAllowed, ""
+	_, err = routingtables.BulkCreateRoutes(fake.ServiceClient(), RoutingTableID, BulkCreateRoutesOpts, routingtables.WithHeader("X-Trace-Id", "trace-42"))
+	th.AssertErr(t, err)
+	th.AssertEquals(t, 2, posts)
+}
+
+func TestToRouteCreateMapOmitsEmptyDescription(t *testing.T) {
+	opts := routingtables.CreateRouteOpts{
+		RoutingTableID: RoutingTableID,
+		CIDR:           "192.168.10.0/24",
+		Gateway:        "10.0.0.10",
+	}
+
+	b, err := opts.ToRouteCreateMap()
+	th.AssertNoErr(t, err)
+
+	route := b["route"].(map[string]interface{})
+	if _, ok := route["description"]; ok {
+		t.Fatalf("expected description to be omitted, got %v", route)
+	}
+	th.AssertEquals(t, "192.168.10.0/24", route["cidr"])
+}
+
+func TestToRouteCreateMapValidation(t *testing.T) {
+	valid := []routingtables.CreateRouteOpts{
+		{RoutingTableID: RoutingTableID, CIDR: "192.168.10.0/24", Gateway: "10.0.0.10"},
+		{RoutingTableID: RoutingTableID, CIDR: "2001:db8::/32", Gateway: "2001:db8::1"},
+	}
+	for _, opts := range valid {
+		_, err := opts.ToRouteCreateMap()
+		th.AssertNoErr(t, err)
+	}
+
+	invalid := []routingtables.CreateRouteOpts{
+		{RoutingTableID: RoutingTableID, CIDR: "10.0.0.0/99", Gateway: "10.0.0.10"},
+		{RoutingTableID: RoutingTableID, CIDR: "10.0.0.0/24", Gateway: "gateway"},
+		{RoutingTableID: RoutingTableID, CIDR: "2001:db8::/129", Gateway: "2001:db8::1"},
+		{RoutingTableID: RoutingTableID, CIDR: "2001:db8::/64", Gateway: "10.0.0.10"},
+		{RoutingTableID: RoutingTableID, CIDR: "10.0.0.0/24", Gateway: "2001:db8::1"},
+	}
+	for _, opts := range invalid {
+		_, err := opts.ToRouteCreateMap()
+		if _, ok := err.(gophercloud.ErrInvalidInput); !ok {
+			t.Errorf("expected gophercloud.ErrInvalidInput for %+v, got %#v", opts, err)
+		}
+	}
+}
+
+func TestGetDefaultRoutingTable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"default_table": "true", "detail": "true"})
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, ListDefaultResponse)
+	})
+
+	rt, err := routingtables.GetDefaultRoutingTable(fake.ServiceClient(), "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "rt-db", rt.Name)
+
+	_, err = routingtables.GetDefaultRoutingTable(fake.ServiceClient(), "unknown-vpc")
+	if _, ok := err.(gophercloud.ErrResourceNotFound); !ok {
+		t.Fatalf("expected gophercloud.ErrResourceNotFound, got %#v", err)
+	}
+}
+
+func TestUpdateTimeOmitted(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/attach_subnet", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, AssociateSubnetResponse)
+	})
+
+	rt, err := routingtables.AssociateSubnet(fake.ServiceClient(), RoutingTableID, "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f").Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, rt.UpdateTime.IsZero())
+}
+
+func TestListAllRoutes(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	HandleListRoutesSuccessfully(t)
+
+	routes, err := routingtables.ListAllRoutes(fake.ServiceClient(), routingtables.RouteListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(routes))
+	th.AssertEquals(t, "192.168.20.0/24", routes[1].CIDR)
+}
+
+func TestListQueryLimitMarker(t *testing.T) {
+	q, err := routingtables.ListOpts{Limit: 50, Marker: RoutingTableID}.ToRoutingTableListQuery()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "?limit=50&marker="+RoutingTableID, q)
+
+	q, err = routingtables.RouteListOpts{RoutingTableID: RoutingTableID, Limit: 20}.ToRouteListQuery()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "?limit=20&routingtable_id="+RoutingTableID, q)
+}
+
+func TestGetNotFound(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("X-Openstack-Request-Id", "req-6f1c2b3a")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	res := routingtables.Get(fake.ServiceClient(), RoutingTableID)
+	th.AssertEquals(t, "req-6f1c2b3a", res.RequestID())
+
+	_, err := res.Extract()
+
+	var notFound routingtables.ErrRoutingTableNotFound
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected ErrRoutingTableNotFound, got %#v", err)
+	}
+	th.AssertEquals(t, RoutingTableID, notFound.ID)
+	th.AssertEquals(t, http.StatusNotFound, notFound.GetStatusCode())
+}
+
+func TestDeleteRouteIfExists(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes/gone", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	th.Mux.HandleFunc("/v2.0/routes/forbidden", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	err := routingtables.DeleteRouteIfExists(fake.ServiceClient(), "gone").ExtractErr()
+	th.AssertNoErr(t, err)
+
+	err = routingtables.DeleteRouteIfExists(fake.ServiceClient(), "forbidden").ExtractErr()
+	th.AssertErr(t, err)
+}
+
+func TestFindRoutingTableByName(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"name": "rt-shared", "detail": "true"})
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, ListByNameResponse)
+	})
+
+	rt, err := routingtables.FindRoutingTableByName(fake.ServiceClient(), "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d", "rt-shared")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "7f8a9b0c-1d2e-4f3a-8b4c-5d6e7f8a9b0c", rt.ID)
+
+	_, err = routingtables.FindRoutingTableByName(fake.ServiceClient(), "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", "rt-shared")
+	multiple, ok := err.(routingtables.ErrMultipleRoutingTablesFound)
+	if !ok {
+		t.Fatalf("expected ErrMultipleRoutingTablesFound, got %#v", err)
+	}
+	th.AssertDeepEquals(t, []string{RoutingTableID, "8a9b0c1d-2e3f-4a4b-9c5d-6e7f8a9b0c1d"}, multiple.IDs)
+}
+
+func TestDeleteRouteIfNotDefault(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes/igw-route", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, `{"route": {"id": "igw-route", "cidr": "0.0.0.0/0", "mask": 0, "gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}}`)
+	})
+
+	err := routingtables.DeleteRouteIfNotDefault(fake.ServiceClient(), "igw-route").ExtractErr()
+	if _, ok := err.(routingtables.ErrDefaultRoute); !ok {
+		t.Fatalf("expected ErrDefaultRoute, got %#v", err)
+	}
+}
+
+func TestAttachGatewayWithRetry(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	calls := 0
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/attach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}`)
+
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, `{"routingtable": {"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}}`)
+	})
+
+	opts := routingtables.AttachGatewayOpts{GatewayID: "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}
+	retry := routingtables.RetryOpts{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	rt, err := routingtables.AttachGatewayWithRetry(fake.ServiceClient(), RoutingTableID, opts, retry).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e", rt.GatewayID)
+	th.AssertEquals(t, 3, calls)
+
+	calls = 0
+	retry.MaxAttempts = 2
+	err = routingtables.AttachGatewayWithRetry(fake.ServiceClient(), RoutingTableID, opts, retry).Err
+	if _, ok := err.(gophercloud.ErrDefault409); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault409, got %#v", err)
+	}
+	th.AssertEquals(t, 2, calls)
+}
+
+func TestAttachGatewayWithRetryWithContext(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	calls := 0
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/attach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "X-Request-Source", "provisioner")
+		calls++
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	opts := routingtables.AttachGatewayOpts{GatewayID: "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}
+	retry := routingtables.RetryOpts{MaxAttempts: 3, InitialBackoff: time.Hour}
+	err := routingtables.AttachGatewayWithRetryWithContext(ctx, fake.ServiceClient(), RoutingTableID, opts, retry,
+		routingtables.WithHeader("X-Request-Source", "provisioner")).Err
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %#v", err)
+	}
+	th.AssertEquals(t, 1, calls)
+}
+
+func TestAttachGatewayWithRetryStaleAttachment(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	gatewayID := "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"
+	puts, gets := 0, 0
+	conflicts := 1
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/attach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		puts++
+		if puts <= conflicts {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"routingtable": {"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}}`)
+	})
+	// The gateway still references the routing table it is being detached
+	// from.
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+gatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		gets++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, strings.Replace(fmt.Sprintf(GetInternetGatewayResponseTemplate, "available"), RoutingTableID, "0c8a5d4e-3f2b-4a1c-9d8e-7f6a5b4c3d2e", 1))
+	})
+
+	opts := routingtables.AttachGatewayOpts{GatewayID: gatewayID}
+	retry := routingtables.RetryOpts{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	rt, err := routingtables.AttachGatewayWithRetry(fake.ServiceClient(), RoutingTableID, opts, retry).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, gatewayID, rt.GatewayID)
+	th.AssertEquals(t, 2, puts)
+	th.AssertEquals(t, 0, gets)
+
+	recorder := &hookRecorder{}
+	routingtables.SetHooks(recorder)
+	defer routingtables.SetHooks(nil)
+
+	puts, gets = 0, 0
+	conflicts = 3
+	err = routingtables.AttachGatewayWithRetry(fake.ServiceClient(), RoutingTableID, opts, retry).Err
+	attached, ok := err.(routingtables.ErrGatewayAlreadyAttached)
+	if !ok {
+		t.Fatalf("expected ErrGatewayAlreadyAttached, got %#v", err)
+	}
+	th.AssertEquals(t, "0c8a5d4e-3f2b-4a1c-9d8e-7f6a5b4c3d2e", attached.AttachedRoutingTableID)
+	th.AssertEquals(t, 3, puts)
+	th.AssertEquals(t, 1, gets)
+	th.AssertDeepEquals(t, []string{
+		"start routingtables.AttachGateway", "end routingtables.AttachGateway false",
+		"start routingtables.AttachGateway", "end routingtables.AttachGateway false",
+		"start routingtables.AttachGateway",
+		"start routingtables.GetGateway", "end routingtables.GetGateway true",
+		"end routingtables.AttachGateway false",
+	}, recorder.events)
+}
+
+func TestToRouteUpdateMapDescription(t *testing.T) {
+	empty := ""
+	b, err := routingtables.UpdateRouteOpts{Description: &empty}.ToRouteUpdateMap()
+	th.AssertNoErr(t, err)
+	th.AssertJSONEquals(t, `{"route": {"description": ""}}`, b)
+
+	b, err = routingtables.UpdateRouteOpts{Gateway: "10.0.0.11"}.ToRouteUpdateMap()
+	th.AssertNoErr(t, err)
+	th.AssertJSONEquals(t, `{"route": {"gateway": "10.0.0.11"}}`, b)
+}
+
+func TestRouteDescriptionLength(t *testing.T) {
+	// 85 three-byte characters are 255 bytes; one more exceeds the limit
+	// although it is only 86 characters long.
+	fits := strings.Repeat("경", 85)
+	tooLong := fits + "로"
+
+	_, err := routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "192.168.10.0/24", Gateway: "10.0.0.10", Description: fits}.ToRouteCreateMap()
+	th.AssertNoErr(t, err)
+	_, err = routingtables.UpdateRouteOpts{Description: &fits}.ToRouteUpdateMap()
+	th.AssertNoErr(t, err)
+
+	_, err = routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "192.168.10.0/24", Gateway: "10.0.0.10", Description: tooLong}.ToRouteCreateMap()
+	invalid, ok := err.(gophercloud.ErrInvalidInput)
+	if !ok {
+		t.Fatalf("expected gophercloud.ErrInvalidInput, got %#v", err)
+	}
+	th.AssertEquals(t, "routingtables.CreateRouteOpts.Description", invalid.Argument)
+	th.AssertEquals(t, true, strings.Contains(invalid.Info, "258 bytes"))
+
+	_, err = routingtables.UpdateRouteOpts{Description: &tooLong}.ToRouteUpdateMap()
+	if _, ok := err.(gophercloud.ErrInvalidInput); !ok {
+		t.Fatalf("expected gophercloud.ErrInvalidInput, got %#v", err)
+	}
+}
+
+func TestDetachAllSubnets(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, GetWithSubnetsResponse)
+	})
+
+	var detached []string
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/detach_subnet", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+
+		var body struct {
+			SubnetID string `json:"subnet_id"`
+		}
+		th.AssertNoErr(t, json.NewDecoder(r.Body).Decode(&body))
+		detached = append(detached, body.SubnetID)
+
+		if body.SubnetID == "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"routingtable": {"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"}}`)
+	})
+
+	err := routingtables.DetachAllSubnets(fake.ServiceClient(), RoutingTableID)
+	th.AssertDeepEquals(t, []string{
+		"c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f",
+		"d2e3f4a5-b6c7-4d8e-9f0a-1b2c3d4e5f6a",
+	}, detached)
+
+	var detachErr routingtables.ErrDetachSubnets
+	if !errors.As(err, &detachErr) {
+		t.Fatalf("expected ErrDetachSubnets, got %#v", err)
+	}
+	th.AssertDeepEquals(t, []string{"c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"}, detachErr.SubnetIDs)
+
+	var conflict gophercloud.ErrDefault409
+	th.AssertEquals(t, true, errors.As(err, &conflict))
+}
+
+func TestSetAsDefaultEmptyBody(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/set_as_default", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+		w.WriteHeader(http.StatusOK)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, SetAsDefaultResponse)
+	})
+
+	rt, err := routingtables.SetAsDefault(fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, rt.DefaultTable)
+}
+
+func TestSetAsDefaultNotDefault(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/set_as_default", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, AssociateSubnetResponse)
+	})
+
+	_, err := routingtables.SetAsDefault(fake.ServiceClient(), RoutingTableID).Extract()
+	if _, ok := err.(routingtables.ErrRoutingTableNotDefault); !ok {
+		t.Fatalf("expected ErrRoutingTableNotDefault, got %#v", err)
+	}
+}
+
+func TestSetAsDefaultMissingRoutingTable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/set_as_default", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"result": "ok"}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{}`)
+	})
+
+	_, err := routingtables.SetAsDefault(fake.ServiceClient(), RoutingTableID).Extract()
+	if _, ok := err.(routingtables.ErrMissingRoutingTable); !ok {
+		t.Fatalf("expected ErrMissingRoutingTable, got %#v", err)
+	}
+}
+
+func TestListLimitAcrossPages(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	var limits []string
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		limits = append(limits, r.URL.Query().Get("limit"))
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("marker") == "" {
+			fmt.Fprintf(w, ListPage1, th.Server.URL+"/v2.0/routingtables?marker=8a9b0c1d-2e3f-4a4b-9c5d-6e7f8a9b0c1d")
+		} else {
+			fmt.Fprint(w, ListDefaultResponse)
+		}
+	})
+
+	tables, err := routingtables.ListAll(fake.ServiceClient(), routingtables.ListOpts{Limit: 200})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(tables))
+	th.AssertDeepEquals(t, []string{"200", "200"}, limits)
+}
+
+func TestCreateRoutesConcurrently(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+
+		var body map[string]map[string]interface{}
+		th.AssertNoErr(t, json.NewDecoder(r.Body).Decode(&body))
+		route := body["route"]
+
+		if route["cidr"] == "192.168.30.0/24" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "id-"+route["cidr"].(string), route["cidr"], route["gateway"])
+	})
+
+	var opts []routingtables.CreateRouteOpts
+	for i := 1; i <= 6; i++ {
+		opts = append(opts, routingtables.CreateRouteOpts{
+			RoutingTableID: RoutingTableID,
+			CIDR:           fmt.Sprintf("192.168.%d0.0/24", i),
+			Gateway:        fmt.Sprintf("10.0.0.%d0", i),
+		})
+	}
+
+	routes, err := routingtables.CreateRoutesConcurrently(fake.ServiceClient(), opts, 0)
+
+	var createErr routingtables.ErrCreateRoutes
+	if !errors.As(err, &createErr) {
+		t.Fatalf("expected ErrCreateRoutes, got %#v", err)
+	}
+	th.AssertEquals(t, 1, len(createErr.Failed))
+	th.AssertEquals(t, "192.168.30.0/24", createErr.Failed[0].CIDR)
+
+	var ids []string
+	for _, r := range routes {
+		ids = append(ids, r.ID)
+	}
+	th.AssertDeepEquals(t, []string{
+		"id-192.168.10.0/24",
+		"id-192.168.20.0/24",
+		"id-192.168.40.0/24",
+		"id-192.168.50.0/24",
+		"id-192.168.60.0/24",
+	}, ids)
+}
+
+func TestCreateQuotaExceeded(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"NeutronError": {"type": "OverQuota", "message": "Quota exceeded for resources: ['routingtable'].", "detail": ""}}`)
+	})
+
+	opts := routingtables.CreateOpts{Name: "rt-load", VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}
+	_, err := routingtables.Create(fake.ServiceClient(), opts).Extract()
+
+	var quotaErr routingtables.ErrQuotaExceeded
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expected ErrQuotaExceeded, got %#v", err)
+	}
+	th.AssertEquals(t, "routingtable", quotaErr.Resource)
+	th.AssertEquals(t, http.StatusConflict, quotaErr.GetStatusCode())
+}
+
+func TestCreateConflict(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"NeutronError": {"type": "Conflict", "message": "VPC is busy"}}`)
+	})
+
+	opts := routingtables.CreateOpts{Name: "rt-load", VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}
+	_, err := routingtables.Create(fake.ServiceClient(), opts).Extract()
+	if _, ok := err.(gophercloud.ErrDefault409); !ok {
+		t.Fatalf("expected ErrDefault409, got %#v", err)
+	}
+}
+
+func TestExtractInto(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	HandleListRoutesSuccessfully(t)
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListDefaultResponse)
+	})
+
+	type RouteRegion struct {
+		Region string `json:"region"`
+	}
+	type annotatedRoute struct {
+		routingtables.Route
+		RouteRegion
+	}
+	var routes []annotatedRoute
+	err := routingtables.ListRoutes(fake.ServiceClient(), routingtables.RouteListOpts{}).EachPage(func(page pagination.Page) (bool, error) {
+		var pageRoutes []annotatedRoute
+		if err := routingtables.ExtractRoutesInto(page, &pageRoutes); err != nil {
+			return false, err
+		}
+		routes = append(routes, pageRoutes...)
+		return true, nil
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(routes))
+	th.AssertEquals(t, "192.168.20.0/24", routes[1].CIDR)
+	th.AssertEquals(t, "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f", routes[1].TenantID)
+
+	var tables []struct {
+		ID           string `json:"id"`
+		DefaultTable bool   `json:"default_table"`
+	}
+	allPages, err := routingtables.List(fake.ServiceClient(), nil).AllPages()
+	th.AssertNoErr(t, err)
+	th.AssertNoErr(t, routingtables.ExtractRoutingTablesInto(allPages, &tables))
+	th.AssertEquals(t, 2, len(tables))
+	th.AssertEquals(t, true, tables[0].DefaultTable)
+}
+
+func TestCloneRoutingTable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	cloneID := "9c0d1e2f-3a4b-4c5d-8e6f-7a8b9c0d1e2f"
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneSourceResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, CloneRequest)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, CloneResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+cloneID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneResponse)
+	})
+
+	var created []map[string]interface{}
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+
+		var body map[string]map[string]interface{}
+		th.AssertNoErr(t, json.NewDecoder(r.Body).Decode(&body))
+		route := body["route"]
+		created = append(created, route)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "id-"+route["cidr"].(string), route["cidr"], route["gateway"])
+	})
+
+	rt, skipped, err := routingtables.CloneRoutingTable(fake.ServiceClient(), RoutingTableID, "rt-web-copy", "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, cloneID, rt.ID)
+
+	th.AssertEquals(t, 2, len(skipped))
+	th.AssertEquals(t, "10.0.0.0/16", skipped[0].CIDR)
+	th.AssertEquals(t, "0.0.0.0/0", skipped[1].CIDR)
+
+	th.AssertDeepEquals(t, []map[string]interface{}{
+		{
+			"routingtable_id": cloneID,
+			"cidr":            "192.168.10.0/24",
+			"gateway":         "10.0.0.10",
+			"description":     "office",
+		},
+	}, created)
+}
+
+func TestCloneRoutingTableWaitsForAvailable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	cloneID := "9c0d1e2f-3a4b-4c5d-8e6f-7a8b9c0d1e2f"
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneSourceResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, strings.Replace(CloneResponse, `"available"`, `"pending"`, 1))
+	})
+	gets := 0
+	th.Mux.HandleFunc("/v2.0/routingtables/"+cloneID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		gets++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if gets == 1 {
+			fmt.Fprint(w, strings.Replace(CloneResponse, `"available"`, `"pending"`, 1))
+			return
+		}
+		fmt.Fprint(w, CloneResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		if gets < 2 {
+			t.Errorf("route created before the routing table became available")
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "id-office", "192.168.10.0/24", "10.0.0.10")
+	})
+
+	rt, _, err := routingtables.CloneRoutingTable(fake.ServiceClient(), RoutingTableID, "rt-web-copy", "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "available", rt.State)
+}
+
+func TestCloneRoutingTableWithoutSkippedRoutes(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	cloneID := "9c0d1e2f-3a4b-4c5d-8e6f-7a8b9c0d1e2f"
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"routingtable": {"id": "%s", "name": "rt-web", "distributed": true, "routes": []}}`, RoutingTableID)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, CloneResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+cloneID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneResponse)
+	})
+
+	_, skipped, err := routingtables.CloneRoutingTable(fake.ServiceClient(), RoutingTableID, "rt-web-copy", "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, skipped != nil && len(skipped) == 0)
+}
+
+func TestCloneRoutingTableNormalizesCIDR(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	cloneID := "9c0d1e2f-3a4b-4c5d-8e6f-7a8b9c0d1e2f"
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"routingtable": {"id": "%s", "name": "rt-web", "distributed": true, "routes": [
+			{"id": "r1", "cidr": "192.168.10.5/24", "mask": 24, "gateway": "10.0.0.10"},
+			{"id": "r2", "cidr": "192.168.10.0", "mask": 24, "gateway": "10.0.0.10"},
+			{"id": "r3", "cidr": "not-a-cidr", "gateway": "10.0.0.10"}
+		]}}`, RoutingTableID)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, CloneResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+cloneID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneResponse)
+	})
+
+	var created []string
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		var body map[string]map[string]interface{}
+		th.AssertNoErr(t, json.NewDecoder(r.Body).Decode(&body))
+		created = append(created, body["route"]["cidr"].(string))
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "new", body["route"]["cidr"], body["route"]["gateway"])
+	})
+
+	_, skipped, err := routingtables.CloneRoutingTable(fake.ServiceClient(), RoutingTableID, "rt-web-copy", "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d")
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, []string{"192.168.10.0/24"}, created)
+	th.AssertEquals(t, 2, len(skipped))
+	th.AssertEquals(t, "r2", skipped[0].ID)
+	th.AssertEquals(t, "r3", skipped[1].ID)
+}
+
+func TestDetachGatewayAndVerify(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/detach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		w.WriteHeader(http.StatusOK)
+	})
+	HandleGetStatesSuccessfully(t, "available")
+
+	rt, err := routingtables.DetachGatewayAndVerify(fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "", rt.GatewayID)
+}
+
+func TestDetachGatewayAndVerifyStillAttached(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/detach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneSourceResponse)
+	})
+
+	_, err := routingtables.DetachGatewayAndVerify(fake.ServiceClient(), RoutingTableID).Extract()
+	attached, ok := err.(routingtables.ErrGatewayStillAttached)
+	if !ok {
+		t.Fatalf("expected ErrGatewayStillAttached, got %#v", err)
+	}
+	th.AssertEquals(t, "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e", attached.GatewayID)
+}
+
+func TestDetachGatewayAndVerifyMissingRoutingTable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/detach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{}`)
+	})
+
+	_, err := routingtables.DetachGatewayAndVerify(fake.ServiceClient(), RoutingTableID).Extract()
+	if _, ok := err.(routingtables.ErrMissingRoutingTable); !ok {
+		t.Fatalf("expected ErrMissingRoutingTable, got %#v", err)
+	}
+}
+
+func TestRawBody(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// Key order and a number beyond float64 precision show whether the body
+	// is returned as received.
+	body := `{"routingtable": {"state": "available", "id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "revision": 12345678901234567890}}`
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, body)
+	})
+
+	res := routingtables.Get(fake.ServiceClient(), RoutingTableID)
+	rt, err := res.Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, RoutingTableID, rt.ID)
+
+	raw, err := res.RawBody()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, body, string(raw))
+}
+
+func TestAttachGatewayAndWait(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	gatewayID := "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/attach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}`)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"routingtable": {"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"}}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneSourceResponse)
+	})
+
+	igwCalls := 0
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+gatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		igwCalls++
+		state := "unavailable"
+		if igwCalls > 1 {
+			state = "available"
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetInternetGatewayResponseTemplate, state)
+	})
+
+	rt, err := routingtables.AttachGatewayAndWait(fake.ServiceClient(), RoutingTableID, gatewayID, 10*time.Second)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, gatewayID, rt.GatewayID)
+	th.AssertEquals(t, 2, igwCalls)
+}
+
+func TestDetailedList(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"detail": "true"})
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListDetailResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/vpcsubnets", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListSubnetsResponse)
+	})
+
+	tables, err := routingtables.DetailedList(fake.ServiceClient(), routingtables.ListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(tables))
+	th.AssertDeepEquals(t, []string{"subnet-web", "subnet-db"}, tables[0].GetSubnetNames())
+}
+
+func TestPing(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	status := http.StatusOK
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"limit": "1"})
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"routingtables": []}`)
+	})
+
+	th.AssertNoErr(t, routingtables.Ping(fake.ServiceClient()))
+
+	status = http.StatusForbidden
+	err := routingtables.Ping(fake.ServiceClient())
+	if _, ok := err.(gophercloud.ErrDefault403); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault403, got %#v", err)
+	}
+
+	status = http.StatusUnauthorized
+	client := fake.ServiceClient()
+	client.ProviderClient.ReauthFunc = func() error {
+		return fmt.Errorf("token expired")
+	}
+	err = routingtables.Ping(client)
+	if _, ok := err.(*gophercloud.ErrUnableToReauthenticate); !ok {
+		t.Fatalf("expected *gophercloud.ErrUnableToReauthenticate, got %#v", err)
+	}
+
+	client.ProviderClient.ReauthFunc = func() error { return nil }
+	err = routingtables.Ping(client)
+	if _, ok := err.(*gophercloud.ErrErrorAfterReauthentication); !ok {
+		t.Fatalf("expected *gophercloud.ErrErrorAfterReauthentication, got %#v", err)
+	}
+
+	client = fake.ServiceClient()
+	client.ResourceBase = "http://127.0.0.1:1/v2.0/"
+	err = routingtables.Ping(client)
+	if _, ok := err.(routingtables.ErrEndpointUnreachable); !ok {
+		t.Fatalf("expected ErrEndpointUnreachable, got %#v", err)
+	}
+}
+
+func TestGetWithTimeout(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// The delay is read by the handler goroutine while the test changes it.
+	var delay atomic.Int64
+	delay.Store(int64(500 * time.Millisecond))
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Duration(delay.Load())):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+
+	_, err := routingtables.Get(fake.ServiceClient(), RoutingTableID, routingtables.WithTimeout(50*time.Millisecond)).Extract()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %#v", err)
+	}
+
+	delay.Store(0)
+	rt, err := routingtables.Get(fake.ServiceClient(), RoutingTableID, routingtables.WithTimeout(5*time.Second)).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "available", rt.State)
+}
+
+func TestGetRoutesForTable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"routingtable_id": RoutingTableID})
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `
+{
+    "routes": [
+        {"id": "c", "cidr": "192.168.20.0/24", "gateway": "10.0.0.20", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "a", "cidr": "10.0.0.0/16", "gateway": "10.0.0.1", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "hidden": true},
+        {"id": "b", "cidr": "192.168.10.0/24", "gateway": "10.0.0.10", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "d", "cidr": "10.0.0.0/8", "gateway": "10.0.0.1", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "e", "cidr": "9.0.0.0/8", "gateway": "10.0.0.1", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"}
+    ]
+}`)
+	})
+
+	routes, err := routingtables.GetRoutesForTable(fake.ServiceClient(), RoutingTableID)
+	th.AssertNoErr(t, err)
+
+	// Numeric order, unlike string order, puts 9.0.0.0/8 first and
+	// 10.0.0.0/8 before 10.0.0.0/16.
+	var ids []string
+	for _, r := range routes {
+		ids = append(ids, r.ID)
+	}
+	th.AssertDeepEquals(t, []string{"e", "d", "a", "b", "c"}, ids)
+}
+
+func TestReplaceRoute(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			th.TestFormValues(t, r, map[string]string{"routingtable_id": RoutingTableID, "cidr": r.URL.Query().Get("cidr")})
+			w.WriteHeader(http.StatusOK)
+			if r.URL.Query().Get("cidr") == "192.168.10.0/24" {
+				fmt.Fprintf(w, `{"routes": [{"id": "route-office", "cidr": "192.168.10.0/24", "gateway": "10.0.0.10", "description": "office", "routingtable_id": "%s"}]}`, RoutingTableID)
+				return
+			}
+			fmt.Fprint(w, `{"routes": []}`)
+		case "POST":
+			th.TestJSONRequest(t, r, `{"route": {"routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "cidr": "192.168.20.0/24", "gateway": "10.0.0.20", "description": "lab"}}`)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, CreateRouteResponseTemplate, "route-lab", "192.168.20.0/24", "10.0.0.20")
+		}
+	})
+	th.Mux.HandleFunc("/v2.0/routes/route-office", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"route": {"gateway": "10.0.0.11", "description": "office"}}`)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "route-office", "192.168.10.0/24", "10.0.0.11")
+	})
+
+	route, change, err := routingtables.ReplaceRoute(fake.ServiceClient(), RoutingTableID, "192.168.10.0/24", "10.0.0.10", "office")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, routingtables.RouteUnchanged, change)
+	th.AssertEquals(t, "route-office", route.ID)
+
+	route, change, err = routingtables.ReplaceRoute(fake.ServiceClient(), RoutingTableID, "192.168.10.0/24", "10.0.0.11", "office")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, routingtables.RouteUpdated, change)
+	th.AssertEquals(t, "10.0.0.11", route.Gateway)
+
+	route, change, err = routingtables.ReplaceRoute(fake.ServiceClient(), RoutingTableID, "192.168.20.0/24", "10.0.0.20", "lab")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, routingtables.RouteCreated, change)
+	th.AssertEquals(t, "route-lab", route.ID)
+
+	// A CIDR with host bits set matches the existing route to its network.
+	route, change, err = routingtables.ReplaceRoute(fake.ServiceClient(), RoutingTableID, "192.168.10.1/24", "10.0.0.11", "office")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, routingtables.RouteUpdated, change)
+	th.AssertEquals(t, "route-office", route.ID)
+
+	route, change, err = routingtables.ReplaceRoute(fake.ServiceClient(), RoutingTableID, "192.168.20.7/24", "10.0.0.20", "lab")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, routingtables.RouteCreated, change)
+	th.AssertEquals(t, "route-lab", route.ID)
+}
+
+func TestCreateRouteIPv6Unsupported(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"NeutronError": {"type": "HTTPBadRequest", "message": "Invalid input for cidr."}}`)
+	})
+
+	opts := routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "2001:db8:10::/48", Gateway: "2001:db8::10"}
+	_, err := routingtables.CreateRoute(fake.ServiceClient(), opts).Extract()
+	unsupported, ok := err.(routingtables.ErrIPv6Unsupported)
+	if !ok {
+		t.Fatalf("expected ErrIPv6Unsupported, got %#v", err)
+	}
+	th.AssertEquals(t, "2001:db8:10::/48", unsupported.CIDR)
+
+	opts = routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "192.168.10.0/24", Gateway: "10.0.0.10"}
+	_, err = routingtables.CreateRoute(fake.ServiceClient(), opts).Extract()
+	if _, ok := err.(gophercloud.ErrDefault400); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault400, got %#v", err)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/gone", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/broken", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	found, failed := routingtables.GetMany(fake.ServiceClient(), []string{RoutingTableID, "gone", "broken", RoutingTableID})
+	th.AssertEquals(t, 1, len(found))
+	th.AssertEquals(t, "rt-web", found[RoutingTableID].Name)
+	th.AssertEquals(t, 2, len(failed))
+
+	var notFound routingtables.ErrRoutingTableNotFound
+	if !errors.As(failed["gone"], &notFound) {
+		t.Fatalf("expected ErrRoutingTableNotFound, got %#v", failed["gone"])
+	}
+	th.AssertEquals(t, "gone", notFound.ID)
+	if errors.As(failed["broken"], &notFound) {
+		t.Fatalf("expected a server error, got %#v", failed["broken"])
+	}
+}
+
+func TestListAllByVPC(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		if r.URL.Query().Get("vpc_id") == "" || r.URL.Query().Get("detail") != "true" {
+			t.Errorf("expected vpc_id and detail query parameters, got %q", r.URL.RawQuery)
+		}
+		// The filter is ignored, as in regions without server-side support.
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListDetailResponse)
+	})
+
+	tables, err := routingtables.ListAll(fake.ServiceClient(), routingtables.ListOpts{VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(tables))
+	th.AssertEquals(t, RoutingTableID, tables[0].ID)
+
+	tables, err = routingtables.ListAll(fake.ServiceClient(), routingtables.ListOpts{VPCID: "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d"})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 0, len(tables))
+}
+
+func TestCreateRouteEmptyBody(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	const routeID = "4b5c6d7e-8f9a-4b0c-9d1e-2f3a4b5c6d7e"
+	location := ""
+	listed := 0
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "POST":
+			if location != "" {
+				w.Header().Add("Location", location)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "GET":
+			listed++
+			q := r.URL.Query()
+			if q.Get("routingtable_id") != RoutingTableID || q.Get("cidr") == "" || q.Get("gateway") != "10.0.0.20" {
+				t.Errorf("unexpected route list query %q", r.URL.RawQuery)
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, ListRoutesPage2)
+		}
+	})
+	th.Mux.HandleFunc("/v2.0/routes/"+routeID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, routeID, "192.168.20.0/24", "10.0.0.20")
+	})
+
+	opts := routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "192.168.20.0/24", Gateway: "10.0.0.20"}
+
+	location = th.Server.URL + "/v2.0/routes/" + routeID
+	route, err := routingtables.CreateRoute(fake.ServiceClient(), opts).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, routeID, route.ID)
+	th.AssertEquals(t, 0, listed)
+
+	location = ""
+	route, err = routingtables.CreateRoute(fake.ServiceClient(), opts).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, routeID, route.ID)
+	th.AssertEquals(t, 1, listed)
+
+	opts.CIDR = "192.168.30.0/24"
+	_, err = routingtables.CreateRoute(fake.ServiceClient(), opts).Extract()
+	notRecovered, ok := err.(routingtables.ErrRouteNotRecovered)
+	if !ok {
+		t.Fatalf("expected ErrRouteNotRecovered, got %#v", err)
+	}
+	th.AssertEquals(t, "192.168.30.0/24", notRecovered.CIDR)
+	th.AssertEquals(t, 0, notRecovered.Matches)
+}
+
+func TestListMalformedLinks(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	page1 := ""
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("marker") == "" {
+			fmt.Fprint(w, page1)
+		} else {
+			fmt.Fprint(w, ListDefaultResponse)
+		}
+	})
+
+	for _, body := range []string{
+		strings.Replace(fmt.Sprintf(ListPage1, th.Server.URL+"/v2.0/routingtables?marker=x"), "routingtables_links", "routingtable_links", 1),
+		strings.Replace(fmt.Sprintf(ListPage1, th.Server.URL+"/v2.0/routingtables?marker=x"), "routingtables_links", "routing_tables_links", 1),
+		fmt.Sprintf(ListPage1, ""),
+		fmt.Sprintf(ListPage1, "/v2.0/routingtables?marker=x"),
+		strings.Replace(ListPage1, `"href": "%s"`, `"href": 1`, 1),
+	} {
+		page1 = body
+		_, err := routingtables.ListAll(fake.ServiceClient(), nil)
+		var malformed routingtables.ErrMalformedPageLinks
+		if !errors.As(err, &malformed) {
+			t.Errorf("expected ErrMalformedPageLinks, got %#v", err)
+		}
+	}
+
+	page1 = fmt.Sprintf(ListPage1, th.Server.URL+"/v2.0/routingtables?marker=x")
+	tables, err := routingtables.ListAll(fake.ServiceClient(), nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(tables))
+
+	// Links of other resources are not misspellings of routingtables_links.
+	page1 = strings.Replace(page1, `"routingtables_links"`, `"subnet_links": [], "routingtables_links"`, 1)
+	tables, err = routingtables.ListAll(fake.ServiceClient(), nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(tables))
+}
+
+func TestToRoutingTableListQuerySort(t *testing.T) {
+	query, err := routingtables.ListOpts{SortKey: routingtables.SortKeyName, SortDir: routingtables.SortDesc}.ToRoutingTableListQuery()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "?sort_dir=desc&sort_key=name", query)
+
+	for _, opts := range []routingtables.ListOpts{
+		{SortDir: "ascending"},
+		{SortKey: "created_at"},
+	} {
+		_, err := opts.ToRoutingTableListQuery()
+		if _, ok := err.(gophercloud.ErrInvalidInput); !ok {
+			t.Errorf("expected gophercloud.ErrInvalidInput for %+v, got %#v", opts, err)
+		}
+	}
+
+	_, err = routingtables.List(fake.ServiceClient(), routingtables.ListOpts{SortDir: "ascending"}).AllPages()
+	if _, ok := err.(gophercloud.ErrInvalidInput); !ok {
+		t.Errorf("expected gophercloud.ErrInvalidInput, got %#v", err)
+	}
+}
+
+func TestProvisionInternetRoutingTable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	gatewayID := "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"
+	defaultResponse := SetAsDefaultResponse
+	var calls []string
+	record := func(r *http.Request) {
+		calls = append(calls, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/v2.0/routingtables"))
+	}
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		record(r)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"routingtable": {"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "name": "rt-web", "state": "available"}}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneSourceResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/attach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		record(r)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneSourceResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/detach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		record(r)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, AssociateSubnetResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/set_as_default", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		record(r)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, defaultResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+gatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetInternetGatewayResponseTemplate, "available")
+	})
+
+	opts := routingtables.CreateOpts{Name: "rt-web", VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}
+	rt, err := routingtables.ProvisionInternetRoutingTable(fake.ServiceClient(), opts, gatewayID, true)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, gatewayID, rt.GatewayID)
+	th.AssertDeepEquals(t, []string{
+		"POST ", "GET /" + RoutingTableID,
+		"PUT /" + RoutingTableID + "/attach_gateway", "GET /" + RoutingTableID,
+		"PUT /" + RoutingTableID + "/set_as_default", "GET /" + RoutingTableID,
+		"GET /" + RoutingTableID,
+	}, calls)
+
+	calls = nil
+	defaultResponse = AssociateSubnetResponse
+	_, err = routingtables.ProvisionInternetRoutingTable(fake.ServiceClient(), opts, gatewayID, true)
+	if _, ok := err.(routingtables.ErrRoutingTableNotDefault); !ok {
+		t.Fatalf("expected ErrRoutingTableNotDefault, got %#v", err)
+	}
+	th.AssertDeepEquals(t, []string{
+		"PUT /" + RoutingTableID + "/set_as_default",
+		"GET /" + RoutingTableID,
+		"PUT /" + RoutingTableID + "/detach_gateway",
+		"DELETE /" + RoutingTableID,
+	}, calls[len(calls)-4:])
+}
+
+func TestProvisionInternetRoutingTableWithContextDeadline(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	var mu sync.Mutex
+	var deleted bool
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"routingtable": {"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "name": "rt-web", "state": "pending"}}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			mu.Lock()
+			deleted = true
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "pending")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	opts := routingtables.CreateOpts{Name: "rt-web", VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}
+	_, err := routingtables.ProvisionInternetRoutingTableWithContext(ctx, fake.ServiceClient(), opts, "", false)
+	if _, ok := err.(gophercloud.ErrTimeOut); !ok {
+		t.Fatalf("expected ErrTimeOut, got %#v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("expected the wait to end at the context deadline, took %s", elapsed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	th.AssertEquals(t, true, deleted)
+}
+
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *hookRecorder) OnRequestStart(operation string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, "start "+operation)
+}
+
+func (h *hookRecorder) OnRequestEnd(operation string, duration time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, fmt.Sprintf("end %s %t", operation, err == nil))
+}
+
+func TestHooks(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+	th.Mux.HandleFunc("/v2.0/routes/gone", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := routingtables.Get(fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+
+	recorder := &hookRecorder{}
+	routingtables.SetHooks(recorder)
+	defer routingtables.SetHooks(nil)
+
+	_, err = routingtables.Get(fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	err = routingtables.DeleteRoute(fake.ServiceClient(), "gone").ExtractErr()
+	th.AssertEquals(t, true, err != nil)
+
+	th.AssertDeepEquals(t, []string{
+		"start routingtables.Get",
+		"end routingtables.Get true",
+		"start routingtables.DeleteRoute",
+		"end routingtables.DeleteRoute false",
+	}, recorder.events)
+}
+
+func TestUpdateDistributed(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	response := fmt.Sprintf(GetResponseTemplate, "available")
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"routingtable": {"distributed": true}}`)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, response)
+	})
+
+	distributed := true
+	opts := routingtables.UpdateOpts{Distributed: &distributed}
+	rt, err := routingtables.Update(fake.ServiceClient(), RoutingTableID, opts).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, rt.Distributed)
+
+	response = CloneSourceResponse
+	_, err = routingtables.Update(fake.ServiceClient(), RoutingTableID, opts).Extract()
+	unchanged, ok := err.(routingtables.ErrDistributedUnchanged)
+	if !ok {
+		t.Fatalf("expected ErrDistributedUnchanged, got %#v", err)
+	}
+	th.AssertEquals(t, true, unchanged.Requested)
+	th.AssertDeepEquals(t, []string{"internet gateway [5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e] is attached"}, unchanged.Reasons)
+}
+
+func TestEachRoute(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	HandleListRoutesSuccessfully(t)
+
+	var cidrs []string
+	err := routingtables.EachRoute(fake.ServiceClient(), routingtables.RouteListOpts{}, func(route routingtables.Route) error {
+		cidrs = append(cidrs, route.CIDR)
+		return nil
+	})
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, []string{"192.168.10.0/24", "192.168.20.0/24"}, cidrs)
+
+	stop := errors.New("stop")
+	cidrs = nil
+	err = routingtables.EachRoute(fake.ServiceClient(), routingtables.RouteListOpts{}, func(route routingtables.Route) error {
+		cidrs = append(cidrs, route.CIDR)
+		return stop
+	})
+	th.AssertEquals(t, stop, err)
+	th.AssertDeepEquals(t, []string{"192.168.10.0/24"}, cidrs)
+}
+
+func TestResourcePathOverride(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	routingtables.ResourcePath = "v1.1/routing-tables"
+	routingtables.RoutesResourcePath = "v1.1/routing-routes"
+	defer func() {
+		routingtables.ResourcePath = "routingtables"
+		routingtables.RoutesResourcePath = "routes"
+	}()
+
+	th.Mux.HandleFunc("/v2.0/v1.1/routing-tables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+	th.Mux.HandleFunc("/v2.0/v1.1/routing-routes/gone", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rt, err := routingtables.Get(fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, RoutingTableID, rt.ID)
+	th.AssertNoErr(t, routingtables.DeleteRoute(fake.ServiceClient(), "gone").ExtractErr())
+}
+
+func TestMoveRoute(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	targetID := "7f8a9b0c-1d2e-4f3a-8b4c-5d6e7f8a9b0c"
+	deleteStatus := map[string]int{"route-office": http.StatusNoContent, "route-moved": http.StatusNoContent}
+	var deleted []string
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, `{"route": {"routingtable_id": "7f8a9b0c-1d2e-4f3a-8b4c-5d6e7f8a9b0c", "cidr": "192.168.10.0/24", "gateway": "10.0.0.10", "description": "office"}}`)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "route-moved", "192.168.10.0/24", "10.0.0.10")
+	})
+	th.Mux.HandleFunc("/v2.0/routes/route-office", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"route": {"id": "route-office", "cidr": "192.168.10.0", "mask": 24, "gateway": "10.0.0.10", "description": "office", "routingtable_id": "%s"}}`, RoutingTableID)
+		case "DELETE":
+			deleted = append(deleted, "route-office")
+			w.WriteHeader(deleteStatus["route-office"])
+		}
+	})
+	th.Mux.HandleFunc("/v2.0/routes/route-moved", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		deleted = append(deleted, "route-moved")
+		w.WriteHeader(deleteStatus["route-moved"])
+	})
+
+	route, err := routingtables.MoveRoute(fake.ServiceClient(), "route-office", targetID)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "route-moved", route.ID)
+	th.AssertDeepEquals(t, []string{"route-office"}, deleted)
+
+	deleted = nil
+	deleteStatus["route-office"] = http.StatusConflict
+	_, err = routingtables.MoveRoute(fake.ServiceClient(), "route-office", targetID)
+	if _, ok := err.(gophercloud.ErrDefault409); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault409, got %#v", err)
+	}
+	th.AssertDeepEquals(t, []string{"route-office", "route-moved"}, deleted)
+
+	deleteStatus["route-moved"] = http.StatusInternalServerError
+	_, err = routingtables.MoveRoute(fake.ServiceClient(), "route-office", targetID)
+	duplicated, ok := err.(routingtables.ErrRouteDuplicated)
+	if !ok {
+		t.Fatalf("expected ErrRouteDuplicated, got %#v", err)
+	}
+	th.AssertEquals(t, "route-moved", duplicated.CopyID)
+
+	route, err = routingtables.MoveRoute(fake.ServiceClient(), "route-office", RoutingTableID)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "route-office", route.ID)
+}
+
+func TestAttachGatewayVerify(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	gatewayID := "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"
+	attached := 0
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/attach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}`)
+		attached++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, CloneSourceResponse)
+	})
+	th.Mux.HandleFunc("/v2.0/internetgateways/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		if r.URL.Path != "/v2.0/internetgateways/5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetInternetGatewayResponseTemplate, "available")
+	})
+
+	opts := routingtables.AttachGatewayOpts{GatewayID: gatewayID, VerifyGateway: true}
+	rt, err := routingtables.AttachGateway(fake.ServiceClient(), RoutingTableID, opts).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, gatewayID, rt.GatewayID)
+	th.AssertEquals(t, 1, attached)
+
+	gatewayID = "0d0e0f10-1112-4314-9516-171819202122"
+	opts = routingtables.AttachGatewayOpts{GatewayID: gatewayID, VerifyGateway: true}
+	_, err = routingtables.AttachGateway(fake.ServiceClient(), RoutingTableID, opts).Extract()
+	notFound, ok := err.(routingtables.ErrGatewayNotFound)
+	if !ok {
+		t.Fatalf("expected ErrGatewayNotFound, got %#v", err)
+	}
+	th.AssertEquals(t, gatewayID, notFound.GatewayID)
+	th.AssertEquals(t, 1, attached)
+
+	// Pointer options are verified too.
+	_, err = routingtables.AttachGateway(fake.ServiceClient(), RoutingTableID, &opts).Extract()
+	if _, ok := err.(routingtables.ErrGatewayNotFound); !ok {
+		t.Fatalf("expected ErrGatewayNotFound for pointer options, got %#v", err)
+	}
+	th.AssertEquals(t, 1, attached)
+}
+
+func TestListRoutesWithTableNames(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	HandleListRoutesSuccessfully(t)
+	listed := 0
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		listed++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListDefaultResponse)
+	})
+
+	routes, err := routingtables.ListRoutesWithTableNames(fake.ServiceClient())
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, listed)
+	th.AssertEquals(t, 2, len(routes))
+	for _, route := range routes {
+		th.AssertEquals(t, RoutingTableID, route.RoutingTableID)
+		th.AssertEquals(t, "rt-web", route.RoutingTableName)
+	}
+	th.AssertEquals(t, "192.168.20.0/24", routes[1].CIDR)
+}
+
+func TestNewRoutingTableOpts(t *testing.T) {
+	b, err := routingtables.NewCentralizedRoutingTable("rt-web", "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c").ToRoutingTableCreateMap()
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, map[string]interface{}{
+		"routingtable": map[string]interface{}{
+			"name":        "rt-web",
+			"vpc_id":      "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c",
+			"distributed": false,
+		},
+	}, b)
+
+	opts := routingtables.NewDistributedRoutingTable("rt-web", "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c")
+	th.AssertEquals(t, true, *opts.Distributed)
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	failures := map[string]int{}
+	calls := map[string]int{}
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		calls["GET"]++
+		if calls["GET"] <= failures["GET"] {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, `{"route": {"routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "cidr": "192.168.10.0/24", "gateway": "10.0.0.10"}}`)
+		calls["POST"]++
+		if calls["POST"] <= failures["POST"] {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "route-office", "192.168.10.0/24", "10.0.0.10")
+	})
+
+	policy := routingtables.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	client := routingtables.WithRetryPolicy(fake.ServiceClient(), policy)
+	routeOpts := routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "192.168.10.0/24", Gateway: "10.0.0.10"}
+
+	failures["GET"] = 2
+	rt, err := routingtables.Get(client, RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, RoutingTableID, rt.ID)
+	th.AssertEquals(t, 3, calls["GET"])
+
+	failures["POST"] = 1
+	_, err = routingtables.CreateRoute(client, routeOpts).Extract()
+	if codeErr, ok := err.(gophercloud.StatusCodeError); !ok || codeErr.GetStatusCode() != http.StatusBadGateway {
+		t.Fatalf("expected a 502 error, got %#v", err)
+	}
+	th.AssertEquals(t, 1, calls["POST"])
+
+	calls["POST"] = 0
+	policy.RetryNonIdempotent = true
+	route, err := routingtables.CreateRoute(routingtables.WithRetryPolicy(fake.ServiceClient(), policy), routeOpts).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "route-office", route.ID)
+	th.AssertEquals(t, 2, calls["POST"])
+
+	calls["GET"] = 0
+	failures["GET"] = 100
+	policy.MaxElapsedTime = 20 * time.Millisecond
+	_, err = routingtables.Get(routingtables.WithRetryPolicy(fake.ServiceClient(), policy), RoutingTableID).Extract()
+	if _, ok := err.(gophercloud.ErrDefault503); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault503, got %#v", err)
+	}
+	if calls["GET"] < 2 || calls["GET"] >= 100 {
+		t.Errorf("expected the retries to stop after the maximum elapsed time, got %d calls", calls["GET"])
+	}
+
+	calls["GET"] = 0
+	_, err = routingtables.Get(fake.ServiceClient(), RoutingTableID).Extract()
+	if _, ok := err.(gophercloud.ErrDefault503); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault503, got %#v", err)
+	}
+	th.AssertEquals(t, 1, calls["GET"])
+}
+
+func TestCreateFillsVPCs(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	response := `{"routingtable": {"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "name": "rt-web", "state": "available"}}`
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, response)
+	})
+
+	opts := routingtables.CreateOpts{Name: "rt-web", VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}
+	rt, err := routingtables.Create(fake.ServiceClient(), opts).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, []string{"a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}, rt.GetVPCIDs())
+
+	response = `{"routingtable": {"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "name": "rt-web", "vpcs": [{"id": "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", "name": "vpc-web"}]}}`
+	rt, err = routingtables.Create(fake.ServiceClient(), opts).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, []string{"vpc-web"}, rt.GetVPCNames())
+}
+
+func TestGetWithHeader(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Trace-Id", "trace-42")
+		th.TestHeader(t, r, "X-Service-Wide", "kept")
+		th.TestHeader(t, r, "X-Auth-Token", fake.TokenID)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+
+	client := fake.ServiceClient()
+	client.MoreHeaders = map[string]string{"X-Service-Wide": "kept"}
+	_, err := routingtables.Get(client, RoutingTableID,
+		routingtables.WithHeader("X-Trace-Id", "trace-42"),
+		routingtables.WithHeader("X-Auth-Token", "forged")).Extract()
+	th.AssertNoErr(t, err)
+
+	// The client passed in is left untouched.
+	th.AssertDeepEquals(t, map[string]string{"X-Service-Wide": "kept"}, client.MoreHeaders)
+}
+
+func TestCreateValidate(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s request", r.Method)
+	})
+
+	_, err := routingtables.Create(fake.ServiceClient(), routingtables.CreateOpts{Name: "rt-web"}).Extract()
+	missing, ok := err.(gophercloud.ErrMissingInput)
+	if !ok {
+		t.Fatalf("expected ErrMissingInput, got %#v", err)
+	}
+	th.AssertEquals(t, "routingtables.CreateOpts.VPCID", missing.Argument)
+	th.AssertEquals(t, "vpc_id is required", err.Error())
+
+	err = routingtables.CreateOpts{VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}.Validate()
+	th.AssertEquals(t, "name is required", err.Error())
+}
+
+func TestRoutingTablePageTotalCount(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	header := "2"
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		if header != "" {
+			w.Header().Add("X-Total-Count", header)
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListDefaultResponse)
+	})
+
+	counts := func() (int, bool) {
+		var count int
+		var ok bool
+		err := routingtables.List(fake.ServiceClient(), nil).EachPage(func(page pagination.Page) (bool, error) {
+			count, ok = page.(routingtables.RoutingTablePage).TotalCount()
+			return false, nil
+		})
+		th.AssertNoErr(t, err)
+		return count, ok
+	}
+
+	count, ok := counts()
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, 2, count)
+
+	header = ""
+	_, ok = counts()
+	th.AssertEquals(t, false, ok)
+
+	header = "many"
+	_, ok = counts()
+	th.AssertEquals(t, false, ok)
+}
+
+func TestCreateRoutingTableIfNotExists(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	vpcID := "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d"
+	existing := `{"routingtables": []}`
+	createStatus := http.StatusCreated
+	posts := 0
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			th.TestFormValues(t, r, map[string]string{"name": "rt-web", "detail": "true"})
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, existing)
+		case "POST":
+			posts++
+			w.WriteHeader(createStatus)
+			if createStatus == http.StatusConflict {
+				fmt.Fprint(w, `{"NeutronError": {"type": "Conflict", "message": "Routing table rt-web already exists"}}`)
+				// The concurrent creation is now visible.
+				existing = `{"routingtables": [{"id": "c1", "name": "rt-web", "vpcs": [{"id": "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d"}]}]}`
+				return
+			}
+			fmt.Fprint(w, `{"routingtable": {"id": "c0", "name": "rt-web", "vpcs": [{"id": "b6e9d3a2-4c7f-4e8b-8d1f-3a2c5b7e9f4d"}]}}`)
+		}
+	})
+
+	opts := routingtables.CreateOpts{Name: "rt-web", VPCID: vpcID}
+
+	rt, created, err := routingtables.CreateRoutingTableIfNotExists(fake.ServiceClient(), opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, created)
+	th.AssertEquals(t, "c0", rt.ID)
+
+	createStatus = http.StatusConflict
+	rt, created, err = routingtables.CreateRoutingTableIfNotExists(fake.ServiceClient(), opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, false, created)
+	th.AssertEquals(t, "c1", rt.ID)
+
+	rt, created, err = routingtables.CreateRoutingTableIfNotExists(fake.ServiceClient(), opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, false, created)
+	th.AssertEquals(t, "c1", rt.ID)
+	th.AssertEquals(t, 2, posts)
+}
+
+type debugRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *debugRecorder) Printf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestDebugLogger(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"routingtable": {"name": "rt-renamed"}}`)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+
+	logger := &debugRecorder{}
+	routingtables.SetDebugLogger(logger)
+	defer routingtables.SetDebugLogger(nil)
+
+	client := fake.ServiceClient()
+	rt, err := routingtables.Update(client, RoutingTableID, routingtables.UpdateOpts{Name: "rt-renamed"}).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, RoutingTableID, rt.ID)
+	if client.ProviderClient.HTTPClient.Transport != nil {
+		t.Fatalf("expected the client to be left untouched")
+	}
+
+	th.AssertEquals(t, 2, len(logger.lines))
+	request, response := logger.lines[0], logger.lines[1]
+	if !strings.HasPrefix(request, "[DEBUG] routingtables.Update request: PUT ") || !strings.Contains(request, `"rt-renamed"`) {
+		t.Fatalf("unexpected request log %q", request)
+	}
+	if strings.Contains(request, fake.TokenID) || !strings.Contains(request, "X-Auth-Token: ***") {
+		t.Fatalf("expected the token to be redacted in %q", request)
+	}
+	if !strings.HasPrefix(response, "[DEBUG] routingtables.Update response: 200") || !strings.Contains(response, RoutingTableID) {
+		t.Fatalf("unexpected response log %q", response)
+	}
+}
+
+func TestGetRouteInTable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes/r1", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, "r1", "192.168.10.0/24", "10.0.0.10")
+	})
+
+	route, err := routingtables.GetRouteInTable(fake.ServiceClient(), RoutingTableID, "r1")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "r1", route.ID)
+
+	otherID := "8a9b0c1d-2e3f-4a4b-9c5d-6e7f8a9b0c1d"
+	_, err = routingtables.GetRouteInTable(fake.ServiceClient(), otherID, "r1")
+	mismatch, ok := err.(routingtables.ErrRouteNotInTable)
+	if !ok {
+		t.Fatalf("expected ErrRouteNotInTable, got %#v", err)
+	}
+	th.AssertEquals(t, otherID, mismatch.RoutingTableID)
+	th.AssertEquals(t, RoutingTableID, mismatch.ActualRoutingTableID)
+}
+
+func TestUpdateRoutingTableCAS(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	gets, puts := 0, 0
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		response := fmt.Sprintf(GetResponseTemplate, "available")
+		switch r.Method {
+		case "GET":
+			gets++
+			if gets > 1 {
+				// Renamed concurrently after the first read.
+				response = strings.Replace(response, `"rt-web"`, `"rt-other"`, 1)
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, response)
+		case "PUT":
+			puts++
+			th.TestJSONRequest(t, r, `{"routingtable": {"distributed": false}}`)
+			if puts == 1 {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, strings.Replace(response, `"distributed": true`, `"distributed": false`, 1))
+		}
+	})
+
+	var seen []string
+	mutate := func(current routingtables.RoutingTable) (routingtables.UpdateOptsBuilder, error) {
+		seen = append(seen, current.Name)
+		distributed := false
+		return routingtables.UpdateOpts{Distributed: &distributed}, nil
+	}
+	retry := routingtables.RetryOpts{InitialBackoff: time.Millisecond}
+
+	rt, err := routingtables.UpdateRoutingTableCAS(fake.ServiceClient(), RoutingTableID, mutate, retry)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, false, rt.Distributed)
+	th.AssertDeepEquals(t, []string{"rt-web", "rt-other", "rt-other"}, seen)
+	th.AssertEquals(t, 6, gets)
+	th.AssertEquals(t, 2, puts)
+
+	puts = 0
+	retry.MaxAttempts = 1
+	_, err = routingtables.UpdateRoutingTableCAS(fake.ServiceClient(), RoutingTableID, mutate, retry)
+	conflict, ok := err.(routingtables.ErrUpdateConflict)
+	if !ok {
+		t.Fatalf("expected ErrUpdateConflict, got %#v", err)
+	}
+	th.AssertEquals(t, 1, conflict.Attempts)
+}
+
+func TestListRoutingTablesByGateway(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	gatewayID := "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"gateway_id": gatewayID})
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `
+{
+    "routingtables": [
+        {"id": "rt-1", "gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"},
+        {"id": "rt-2", "gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"}
+    ]
+}`)
+	})
+
+	tables, err := routingtables.ListRoutingTablesByGateway(fake.ServiceClient(), gatewayID)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(tables))
+	th.AssertEquals(t, "rt-2", tables[1].ID)
+}
+
+func TestEmptyResultsAreNotNil(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"routingtables": []}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"routes": null}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/related_gateways", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{}`)
+	})
+
+	tables, err := routingtables.ListAll(fake.ServiceClient(), routingtables.ListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, tables != nil && len(tables) == 0)
+
+	routes, err := routingtables.ListAllRoutes(fake.ServiceClient(), routingtables.RouteListOpts{})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, routes != nil && len(routes) == 0)
+
+	gateways, err := routingtables.GetRelatedGateways(fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, gateways != nil && len(gateways) == 0)
+
+	filtered := routingtables.FilterRoutingTablesByVPC(nil, "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c")
+	th.AssertEquals(t, true, filtered != nil && len(filtered) == 0)
+	th.AssertEquals(t, true, routingtables.FilterGatewaysByType(nil, routingtables.GatewayTypeInternetGateway) != nil)
+	th.AssertEquals(t, true, routingtables.FilterRoutingTablesByTime(nil, time.Time{}, time.Time{}) != nil)
+
+	var rt routingtables.RoutingTable
+	th.AssertEquals(t, true, rt.GetSubnetIDs() != nil && rt.GetVPCNames() != nil)
+}
+
+func TestListAllByIDs(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	wantIDs := []string{"rt-1", "rt-3"}
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.AssertDeepEquals(t, wantIDs, r.URL.Query()["id"])
+
+		// Answer as a region ignoring the id filter.
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"routingtables": [{"id": "rt-1"}, {"id": "rt-2"}, {"id": "rt-3"}]}`)
+	})
+
+	query, err := routingtables.ListOpts{IDs: []string{"rt-1", "rt-3"}}.ToRoutingTableListQuery()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "?id=rt-1&id=rt-3", query)
+
+	tables, err := routingtables.ListAll(fake.ServiceClient(), routingtables.ListOpts{IDs: []string{"rt-1", "rt-3"}})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(tables))
+	th.AssertEquals(t, "rt-1", tables[0].ID)
+	th.AssertEquals(t, "rt-3", tables[1].ID)
+
+	// ID joins the set instead of being sent twice or ignored.
+	opts := routingtables.ListOpts{ID: "rt-2", IDs: []string{"rt-1", "rt-2"}}
+	query, err = opts.ToRoutingTableListQuery()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "?id=rt-2&id=rt-1", query)
+
+	wantIDs = []string{"rt-2", "rt-1"}
+	tables, err = routingtables.ListAll(fake.ServiceClient(), opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(tables))
+	th.AssertEquals(t, "rt-1", tables[0].ID)
+	th.AssertEquals(t, "rt-2", tables[1].ID)
+}
+
+func TestCreateRouteAndGetTable(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	const routeID = "2c3d4e5f-6a7b-4c8d-9e0f-1a2b3c4d5e6f"
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, CreateRouteResponseTemplate, routeID, "192.168.10.0/24", "10.0.0.10")
+	})
+	status := http.StatusOK
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			fmt.Fprintf(w, GetResponseTemplate, "available")
+		}
+	})
+
+	opts := routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "192.168.10.0/24", Gateway: "10.0.0.10"}
+	rt, route, err := routingtables.CreateRouteAndGetTable(fake.ServiceClient(), opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, routeID, route.ID)
+	th.AssertEquals(t, RoutingTableID, rt.ID)
+	th.AssertEquals(t, 1, len(rt.Routes))
+
+	status = http.StatusServiceUnavailable
+	rt, route, err = routingtables.CreateRouteAndGetTable(fake.ServiceClient(), opts)
+	th.AssertErr(t, err)
+	th.AssertEquals(t, true, rt == nil)
+	th.AssertEquals(t, routeID, route.ID)
+}
+
+func TestCreateRouteAndGetTableWithoutRoute(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"route": null}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+
+	opts := routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "192.168.10.0/24", Gateway: "10.0.0.10"}
+	rt, route, err := routingtables.CreateRouteAndGetTable(fake.ServiceClient(), opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, route == nil)
+	th.AssertEquals(t, RoutingTableID, rt.ID)
+}
+
+func TestWithTenant(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	tenantID := "3f2e1d0c9b8a4f7e6d5c4b3a2f1e0d9c"
+	th.Mux.HandleFunc("/v2.0/routingtables", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			th.TestFormValues(t, r, map[string]string{"tenant_id": tenantID, "name": "rt-web"})
+			th.TestHeader(t, r, "X-Trace-Id", "trace-42")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"routingtables": []}`)
+		case "POST":
+			th.TestJSONRequest(t, r, `{"routingtable": {"name": "rt-web", "vpc_id": "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", "tenant_id": "3f2e1d0c9b8a4f7e6d5c4b3a2f1e0d9c"}}`)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, GetResponseTemplate, "available")
+		}
+	})
+
+	// A tenant set in the options is replaced by the one of WithTenant.
+	listOpts := routingtables.ListOpts{Name: "rt-web", TenantID: "9b4d1e2b6f4c4e1f8f6a2a0b3c8d7e6f"}
+	pager := routingtables.List(fake.ServiceClient(), listOpts, routingtables.WithTenant(tenantID), routingtables.WithHeader("X-Trace-Id", "trace-42"))
+	allPages, err := pager.AllPages()
+	th.AssertNoErr(t, err)
+	tables, err := routingtables.ExtractRoutingTables(allPages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 0, len(tables))
+
+	opts := routingtables.CreateOpts{Name: "rt-web", VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}
+	_, err = routingtables.Create(fake.ServiceClient(), opts, routingtables.WithTenant(tenantID)).Extract()
+	th.AssertNoErr(t, err)
+
+	b, err := routingtables.CreateOpts{Name: "rt-web", VPCID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", TenantID: tenantID}.ToRoutingTableCreateMap()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, tenantID, b["routingtable"].(map[string]interface{})["tenant_id"])
+}
+
+func TestListRoutesWithRequestOptions(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	tenantID := "3f2e1d0c9b8a4f7e6d5c4b3a2f1e0d9c"
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"tenant_id": tenantID, "routingtable_id": RoutingTableID})
+		th.TestHeader(t, r, "X-Trace-Id", "trace-42")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ListRoutesPage2)
+	})
+
+	opts := routingtables.RouteListOpts{RoutingTableID: RoutingTableID}
+	allPages, err := routingtables.ListRoutes(fake.ServiceClient(), opts, routingtables.WithTenant(tenantID), routingtables.WithHeader("X-Trace-Id", "trace-42")).AllPages()
+	th.AssertNoErr(t, err)
+	routes, err := routingtables.ExtractRoutes(allPages)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 1, len(routes))
+}
+
+func TestDetachGatewayFromDefault(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	detaches := 0
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/detach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		detaches++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"NeutronError": {"type": "BadRequest", "message": "Cannot detach gateway from default routing table"}}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, strings.Replace(fmt.Sprintf(GetResponseTemplate, "available"), `"default_table": false`, `"default_table": true`, 1))
+	})
+
+	err := routingtables.DetachGateway(fake.ServiceClient(), RoutingTableID).Err
+	refused, ok := err.(routingtables.ErrCannotDetachDefault)
+	if !ok {
+		t.Fatalf("expected ErrCannotDetachDefault, got %#v", err)
+	}
+	th.AssertEquals(t, http.StatusBadRequest, refused.GetStatusCode())
+	th.AssertEquals(t, "Cannot detach gateway from default routing table", refused.Message)
+
+	_, err = routingtables.DetachGatewayAndVerify(fake.ServiceClient(), RoutingTableID).Extract()
+	if _, ok := err.(routingtables.ErrCannotDetachDefault); !ok {
+		t.Fatalf("expected ErrCannotDetachDefault, got %#v", err)
+	}
+	th.AssertEquals(t, 2, detaches)
+
+	err = routingtables.DetachGateway(fake.ServiceClient(), RoutingTableID, routingtables.WithDefaultTableCheck()).Err
+	if _, ok := err.(routingtables.ErrCannotDetachDefault); !ok {
+		t.Fatalf("expected ErrCannotDetachDefault, got %#v", err)
+	}
+	th.AssertEquals(t, 2, detaches)
+}
+
+func TestDetachGatewayOtherErrors(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID+"/detach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"NeutronError": {"type": "HTTPBadRequest", "message": "Invalid input: default value of gateway_id cannot be used"}}`)
+	})
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{}`)
+	})
+
+	err := routingtables.DetachGateway(fake.ServiceClient(), RoutingTableID).Err
+	if _, ok := err.(gophercloud.ErrDefault400); !ok {
+		t.Fatalf("expected gophercloud.ErrDefault400, got %#v", err)
+	}
+
+	err = routingtables.DetachGateway(fake.ServiceClient(), RoutingTableID, routingtables.WithDefaultTableCheck()).Err
+	if _, ok := err.(routingtables.ErrMissingRoutingTable); !ok {
+		t.Fatalf("expected ErrMissingRoutingTable, got %#v", err)
+	}
+}
+
+func TestResetRoutes(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"routingtable_id": RoutingTableID})
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `
+{
+    "routes": [
+        {"id": "system", "cidr": "10.0.0.0/16", "gateway": "10.0.0.1", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4", "hidden": true},
+        {"id": "igw", "cidr": "0.0.0.0/0", "gateway": "10.0.0.1", "gateway_id": "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "r1", "cidr": "192.168.10.0/24", "gateway": "10.0.0.10", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "r2", "cidr": "192.168.20.0/24", "gateway": "10.0.0.20", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "r3", "cidr": "192.168.30.0/24", "gateway": "10.0.0.30", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"},
+        {"id": "other", "cidr": "192.168.40.0/24", "gateway": "10.0.0.40", "routingtable_id": "8a9b0c1d-2e3f-4a4b-9c5d-6e7f8a9b0c1d"}
+    ]
+}`)
+	})
+
+	var deletedIDs []string
+	th.Mux.HandleFunc("/v2.0/routes/", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		id := strings.TrimPrefix(r.URL.Path, "/v2.0/routes/")
+		deletedIDs = append(deletedIDs, id)
+		switch id {
+		case "r2":
+			w.WriteHeader(http.StatusNotFound)
+		case "r3":
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	deleted, err := routingtables.ResetRoutes(fake.ServiceClient(), RoutingTableID)
+	th.AssertEquals(t, 2, deleted)
+	th.AssertDeepEquals(t, []string{"r1", "r2", "r3"}, deletedIDs)
+
+	reset, ok := err.(routingtables.ErrResetRoutes)
+	if !ok {
+		t.Fatalf("expected ErrResetRoutes, got %#v", err)
+	}
+	th.AssertDeepEquals(t, []string{"r3"}, reset.RouteIDs)
+	if _, ok := reset.Failures["r3"].(gophercloud.ErrDefault409); !ok {
+		t.Fatalf("expected ErrDefault409, got %#v", reset.Failures["r3"])
+	}
+}
+
+func TestGetDetailed(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestFormValues(t, r, map[string]string{"detail": "true"})
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, GetWithSubnetsResponse)
+	})
+
+	rt, err := routingtables.GetDetailed(fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(rt.Subnets))
+	th.AssertEquals(t, "subnet-db", rt.Subnets[1].Name)
+}
+
+func TestCreateRouteOrGet(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	createStatus := http.StatusCreated
+	listed := `{"routes": [{"id": "existing", "cidr": "192.168.10.0/24", "gateway": "10.0.0.11", "routingtable_id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4"}]}`
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "POST":
+			w.WriteHeader(createStatus)
+			if createStatus == http.StatusConflict {
+				fmt.Fprint(w, `{"NeutronError": {"type": "Conflict", "message": "Route 192.168.10.0/24 already exists"}}`)
+				return
+			}
+			fmt.Fprintf(w, CreateRouteResponseTemplate, "new", "192.168.10.0/24", "10.0.0.10")
+		case "GET":
+			th.TestFormValues(t, r, map[string]string{"routingtable_id": RoutingTableID, "cidr": "192.168.10.0/24"})
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, listed)
+		}
+	})
+
+	opts := routingtables.CreateRouteOpts{RoutingTableID: RoutingTableID, CIDR: "192.168.10.0/24", Gateway: "10.0.0.10"}
+	route, created, err := routingtables.CreateRouteOrGet(fake.ServiceClient(), opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, created)
+	th.AssertEquals(t, "new", route.ID)
+
+	createStatus = http.StatusConflict
+	route, created, err = routingtables.CreateRouteOrGet(fake.ServiceClient(), opts)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, false, created)
+	th.AssertEquals(t, "existing", route.ID)
+	th.AssertEquals(t, "10.0.0.11", route.Gateway)
+
+	listed = `{"routes": []}`
+	_, _, err = routingtables.CreateRouteOrGet(fake.ServiceClient(), opts)
+	if _, ok := err.(gophercloud.ErrDefault409); !ok {
+		t.Fatalf("expected ErrDefault409, got %#v", err)
+	}
+}
+
+func TestSetEndpointForTesting(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/mock/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+	th.Mux.HandleFunc("/mock/routes/gone", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	restore := routingtables.SetEndpointForTesting(th.Endpoint() + "mock/")
+	defer restore()
+
+	rt, err := routingtables.Get(fake.ServiceClient(), RoutingTableID).Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, RoutingTableID, rt.ID)
+	th.AssertNoErr(t, routingtables.DeleteRoute(fake.ServiceClient(), "gone").ExtractErr())
+}
+
+func TestDeleteRoutes(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	status := map[string]int{"r1": http.StatusNoContent, "r2": http.StatusConflict, "r3": http.StatusNoContent, "r4": http.StatusNotFound}
+	for id, code := range status {
+		id, code := id, code
+		th.Mux.HandleFunc("/v2.0/routes/"+id, func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(t, r, "DELETE")
+			mu.Lock()
+			calls[id]++
+			mu.Unlock()
+			w.WriteHeader(code)
+		})
+	}
+
+	deleted, errs := routingtables.DeleteRoutes(fake.ServiceClient(), []string{"r1", "r2", "r3", "r4", "r1"})
+	th.AssertDeepEquals(t, []string{"r1", "r3"}, deleted)
+	th.AssertEquals(t, 2, len(errs))
+	if _, ok := errs["r2"].(gophercloud.ErrDefault409); !ok {
+		t.Fatalf("expected ErrDefault409 for r2, got %#v", errs["r2"])
+	}
+	if _, ok := errs["r4"].(gophercloud.ErrDefault404); !ok {
+		t.Fatalf("expected ErrDefault404 for r4, got %#v", errs["r4"])
+	}
+	th.AssertDeepEquals(t, map[string]int{"r1": 1, "r2": 1, "r3": 1, "r4": 1}, calls)
+
+	deleted, errs = routingtables.DeleteRoutes(fake.ServiceClient(), nil)
+	th.AssertEquals(t, 0, len(deleted))
+	th.AssertEquals(t, 0, len(errs))
+}
+
+func TestAttachGatewayAlreadyAttached(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	gatewayID := "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"
+	targetID := "7f8a9b0c-1d2e-4f3a-8b4c-5d6e7f8a9b0c"
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+targetID+"/attach_gateway", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"NeutronError": {"type": "Conflict", "message": "Conflict"}}`)
+	})
+
+	igwStatus := http.StatusOK
+	igwResponse := fmt.Sprintf(GetInternetGatewayResponseTemplate, "available")
+	th.Mux.HandleFunc("/v2.0/internetgateways/"+gatewayID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(igwStatus)
+		fmt.Fprint(w, igwResponse)
+	})
+
+	opts := routingtables.AttachGatewayOpts{GatewayID: gatewayID}
+	err := routingtables.AttachGateway(fake.ServiceClient(), targetID, opts).Err
+	var attached routingtables.ErrGatewayAlreadyAttached
+	if !errors.As(err, &attached) {
+		t.Fatalf("expected ErrGatewayAlreadyAttached, got %#v", err)
+	}
+	th.AssertEquals(t, gatewayID, attached.GatewayID)
+	th.AssertEquals(t, targetID, attached.RoutingTableID)
+	th.AssertEquals(t, RoutingTableID, attached.AttachedRoutingTableID)
+	th.AssertEquals(t, http.StatusConflict, attached.Actual)
+
+	// A conflict that cannot be explained is left for the caller to retry.
+	igwStatus = http.StatusNotFound
+	err = routingtables.AttachGateway(fake.ServiceClient(), targetID, opts).Err
+	if _, ok := err.(gophercloud.ErrDefault409); !ok {
+		t.Fatalf("expected ErrDefault409 for an unknown gateway, got %#v", err)
+	}
+
+	igwStatus = http.StatusOK
+	igwResponse = strings.Replace(igwResponse, `"`+RoutingTableID+`"`, "null", 1)
+	err = routingtables.AttachGateway(fake.ServiceClient(), targetID, opts).Err
+	if _, ok := err.(gophercloud.ErrDefault409); !ok {
+		t.Fatalf("expected ErrDefault409 for a detached gateway, got %#v", err)
+	}
+}
+
+func TestListAllRoutesConcurrently(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	var mu sync.Mutex
+	var pages []string
+	th.Mux.HandleFunc("/v2.0/routes", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		page := r.URL.Query().Get("page")
+		mu.Lock()
+		pages = append(pages, page)
+		mu.Unlock()
+
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Total-Count", "3")
+		w.WriteHeader(http.StatusOK)
+		if page == "" {
+			fmt.Fprintf(w, ListRoutesPage1, th.Server.URL+"/v2.0/routes?page=2")
+			return
+		}
+		// The numbered pages do not link to each other, so that only a
+		// concurrent listing reaches the third one.
+		fmt.Fprintf(w, `{"routes": [{"id": "route-%s", "cidr": "192.168.%s0.0/24"}]}`, page, page)
+	})
+
+	routes, err := routingtables.ListAllRoutesConcurrently(fake.ServiceClient(), routingtables.RouteListOpts{}, 2)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, len(routes))
+	th.AssertEquals(t, "3a4b5c6d-7e8f-4a9b-8c0d-1e2f3a4b5c6d", routes[0].ID)
+	th.AssertEquals(t, "route-2", routes[1].ID)
+	th.AssertEquals(t, "route-3", routes[2].ID)
+	th.AssertEquals(t, 3, len(pages))
+}
+
+func TestListAllRoutesConcurrentlyFollowsLinks(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	HandleListRoutesSuccessfully(t)
+
+	routes, err := routingtables.ListAllRoutesConcurrently(fake.ServiceClient(), nil, 0)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2, len(routes))
+	th.AssertEquals(t, "192.168.10.0/24", routes[0].CIDR)
+	th.AssertEquals(t, "192.168.20.0/24", routes[1].CIDR)
+}
+
+func TestPreviewUpdate(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v2.0/routingtables/"+RoutingTableID, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, GetResponseTemplate, "available")
+	})
+
+	distributed, centralized := true, false
+
+	changes, err := routingtables.PreviewUpdate(fake.ServiceClient(), RoutingTableID, routingtables.UpdateOpts{Name: "rt-web"})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 0, len(changes))
+
+	changes, err = routingtables.PreviewUpdate(fake.ServiceClient(), RoutingTableID, routingtables.UpdateOpts{Name: "rt-web", Distributed: &distributed})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 0, len(changes))
+
+	changes, err = routingtables.PreviewUpdate(fake.ServiceClient(), RoutingTableID, routingtables.UpdateOpts{Name: "rt-app", Distributed: &centralized})
+	th.AssertNoErr(t, err)
+	th.AssertDeepEquals(t, map[string]interface{}{"name": "rt-app", "distributed": false}, changes)
+}
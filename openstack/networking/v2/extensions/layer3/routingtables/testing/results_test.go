@@ -0,0 +1,349 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/routingtables"
+	th "github.com/cloud-barista/nhncloud-sdk-go/testhelper"
+)
+
+func TestDiffRoutes(t *testing.T) {
+	office := "office"
+	current := []routingtables.Route{
+		{ID: "system", CIDR: "10.0.0.0/16", Gateway: "10.0.0.1", Hidden: true},
+		{ID: "keep", CIDR: "192.168.10.0/24", Gateway: "10.0.0.10"},
+		{ID: "stale", CIDR: "192.168.30.0/24", Gateway: "10.0.0.30"},
+	}
+	desired := []routingtables.Route{
+		{CIDR: "192.168.10.0/24", Gateway: "10.0.0.10", Description: &office},
+		{CIDR: "192.168.20.0/24", Gateway: "10.0.0.20"},
+	}
+
+	toAdd, toDelete := routingtables.DiffRoutes(current, desired)
+	th.AssertDeepEquals(t, []routingtables.CreateRouteOpts{
+		{CIDR: "192.168.20.0/24", Gateway: "10.0.0.20"},
+	}, toAdd)
+	th.AssertDeepEquals(t, []string{"stale"}, toDelete)
+
+	toAdd, toDelete = routingtables.DiffRoutesWithDescription(current, desired)
+	th.AssertEquals(t, 2, len(toAdd))
+	th.AssertEquals(t, "office", toAdd[0].Description)
+	th.AssertDeepEquals(t, []string{"keep", "stale"}, toDelete)
+}
+
+func TestRouteIsDefault(t *testing.T) {
+	igw := "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e"
+	th.AssertEquals(t, true, routingtables.Route{CIDR: "10.0.0.0/16", Hidden: true}.IsDefault())
+	th.AssertEquals(t, true, routingtables.Route{CIDR: "0.0.0.0/0", GatewayID: igw}.IsDefault())
+	th.AssertEquals(t, true, routingtables.Route{CIDR: "0.0.0.0", Mask: 0, GatewayID: igw}.IsDefault())
+	th.AssertEquals(t, false, routingtables.Route{CIDR: "0.0.0.0/0", Gateway: "10.0.0.10"}.IsDefault())
+	th.AssertEquals(t, false, routingtables.Route{CIDR: "192.168.10.0/24", GatewayID: igw}.IsDefault())
+}
+
+func TestFilterRoutingTablesByTime(t *testing.T) {
+	at := func(hour int) routingtables.NHNCloudTime {
+		return routingtables.NHNCloudTime{Time: time.Date(2024, 3, 2, hour, 0, 0, 0, time.UTC)}
+	}
+	tables := []routingtables.RoutingTable{
+		{ID: "early", CreateTime: at(1)},
+		{ID: "middle", CreateTime: at(5)},
+		{ID: "late", CreateTime: at(9)},
+		{ID: "unknown"},
+	}
+	ids := func(tables []routingtables.RoutingTable) []string {
+		var ids []string
+		for _, rt := range tables {
+			ids = append(ids, rt.ID)
+		}
+		return ids
+	}
+
+	after := at(5).Time
+	before := at(9).Time
+	th.AssertDeepEquals(t, []string{"middle"}, ids(routingtables.FilterRoutingTablesByTime(tables, after, before)))
+	th.AssertDeepEquals(t, []string{"middle", "late"}, ids(routingtables.FilterRoutingTablesByTime(tables, after, time.Time{})))
+	th.AssertDeepEquals(t, []string{"early", "middle"}, ids(routingtables.FilterRoutingTablesByTime(tables, time.Time{}, before)))
+	th.AssertEquals(t, 4, len(routingtables.FilterRoutingTablesByTime(tables, time.Time{}, time.Time{})))
+}
+
+func TestRouteNormalizedCIDR(t *testing.T) {
+	r := routingtables.Route{CIDR: "192.168.10.5/24"}
+	cidr, err := r.NormalizedCIDR()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "192.168.10.0/24", cidr)
+	th.AssertEquals(t, 24, r.Mask)
+
+	r = routingtables.Route{CIDR: "192.168.10.0", Mask: 24}
+	cidr, err = r.NormalizedCIDR()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "192.168.10.0/24", cidr)
+
+	r = routingtables.Route{CIDR: "0.0.0.0"}
+	cidr, err = r.NormalizedCIDR()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "0.0.0.0/0", cidr)
+
+	r = routingtables.Route{CIDR: "2001:db8::/32", Mask: 32}
+	cidr, err = r.NormalizedCIDR()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2001:db8::/32", cidr)
+
+	r = routingtables.Route{CIDR: "2001:db8:0:10::", Mask: 64}
+	cidr, err = r.NormalizedCIDR()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2001:db8:0:10::/64", cidr)
+
+	r = routingtables.Route{CIDR: "2001:db8::/120"}
+	cidr, err = r.NormalizedCIDR()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "2001:db8::/120", cidr)
+	th.AssertEquals(t, 120, r.Mask)
+
+	for _, r := range []routingtables.Route{
+		{CIDR: "192.168.10.0/24", Mask: 16},
+		{CIDR: "192.168.10.0"},
+		{CIDR: "192.168.10.0", Mask: 33},
+		{CIDR: "2001:db8::", Mask: 129},
+		{CIDR: "not-a-cidr"},
+	} {
+		_, err := r.NormalizedCIDR()
+		th.AssertErr(t, err)
+	}
+}
+
+func TestFlexibleVPCInfoMarshalMode(t *testing.T) {
+	vpc := routingtables.FlexibleVPCInfo{ID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", Name: "vpc-web"}
+
+	b, err := json.Marshal(vpc)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, `{"id":"a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c","name":"vpc-web"}`, string(b))
+
+	b, err = json.Marshal(vpc.AsID())
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, `"a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"`, string(b))
+
+	// Clearing the name must not change the format of an object-mode value.
+	obj := vpc.AsObject()
+	obj.Name = ""
+	b, err = json.Marshal(obj)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, `{"id":"a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}`, string(b))
+}
+
+func TestFlexibleInfoRoundTrip(t *testing.T) {
+	for _, vpc := range []routingtables.FlexibleVPCInfo{
+		{ID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"},
+		{ID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c", Name: "vpc-web"},
+		routingtables.FlexibleVPCInfo{ID: "a5d8c2f1-3b6e-4d7a-9c0e-2f1b4a6d8e3c"}.AsObject(),
+	} {
+		b, err := json.Marshal(vpc)
+		th.AssertNoErr(t, err)
+
+		decoded := routingtables.FlexibleVPCInfo{MarshalMode: vpc.MarshalMode}
+		th.AssertNoErr(t, json.Unmarshal(b, &decoded))
+		th.AssertDeepEquals(t, vpc, decoded)
+
+		again, err := json.Marshal(decoded)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, string(b), string(again))
+	}
+
+	for _, subnet := range []routingtables.FlexibleSubnetInfo{
+		{ID: "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"},
+		routingtables.FlexibleSubnetInfo{ID: "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f", Name: "subnet-web"}.AsID(),
+		routingtables.FlexibleSubnetInfo{ID: "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"}.AsObject(),
+	} {
+		b, err := json.Marshal(subnet)
+		th.AssertNoErr(t, err)
+
+		decoded := routingtables.FlexibleSubnetInfo{MarshalMode: subnet.MarshalMode}
+		th.AssertNoErr(t, json.Unmarshal(b, &decoded))
+		if subnet.MarshalMode == routingtables.MarshalAsID {
+			subnet.Name = ""
+		}
+		th.AssertDeepEquals(t, subnet, decoded)
+	}
+}
+
+func TestRoutingTableCounts(t *testing.T) {
+	var rt routingtables.RoutingTable
+	th.AssertEquals(t, 0, rt.RouteCount())
+	th.AssertEquals(t, 0, rt.SubnetCount())
+
+	err := json.Unmarshal([]byte(`{
+		"id": "6e7ef4ad-9a5c-4a41-9e1f-1b3cb1d3b1c4",
+		"subnets": ["c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f", {"id": "d2e3f4a5-b6c7-4d8e-9f0a-1b2c3d4e5f6a", "name": "subnet-db"}],
+		"routes": [{"id": "r1", "cidr": "10.0.0.0/16"}, {"id": "r2", "cidr": "192.168.10.0/24"}, {"id": "r3", "cidr": "192.168.20.0/24"}]
+	}`), &rt)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 3, rt.RouteCount())
+	th.AssertEquals(t, 2, rt.SubnetCount())
+}
+
+func TestExtractStrict(t *testing.T) {
+	var body interface{}
+	err := json.Unmarshal([]byte(`{"routingtable": {"id": "5c1e2f3a-4b5c-4d6e-8f7a-9b0c1d2e3f4a", "name": "rt-web", "distributed": "yes"}}`), &body)
+	th.AssertNoErr(t, err)
+	r := routingtables.RoutingTableResult{Result: gophercloud.Result{Body: body}}
+
+	rt, err := r.Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "rt-web", rt.Name)
+	th.AssertEquals(t, false, rt.Distributed)
+
+	_, err = r.ExtractStrict()
+	if _, ok := err.(*json.UnmarshalTypeError); !ok {
+		t.Fatalf("expected *json.UnmarshalTypeError, got %#v", err)
+	}
+
+	routingtables.StrictExtract = true
+	defer func() { routingtables.StrictExtract = false }()
+	_, err = r.Extract()
+	if _, ok := err.(*json.UnmarshalTypeError); !ok {
+		t.Fatalf("expected *json.UnmarshalTypeError, got %#v", err)
+	}
+}
+
+func TestRoutingTableStateIsTerminal(t *testing.T) {
+	th.AssertEquals(t, true, routingtables.StateAvailable.IsTerminal())
+	th.AssertEquals(t, true, routingtables.StateError.IsTerminal())
+	th.AssertEquals(t, false, routingtables.StatePending.IsTerminal())
+	th.AssertEquals(t, false, routingtables.RoutingTableState("").IsTerminal())
+}
+
+func TestFilterGatewaysByType(t *testing.T) {
+	gateways := []routingtables.Gateway{
+		{ID: "5c1e0d8a-2f3b-4a6c-9d7e-8f0a1b2c3d4e", Type: "internetgateway", Name: "igw-web"},
+		{ID: "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d", Type: "peering", Name: "peer-db"},
+		{ID: "1f2e3d4c-5b6a-4978-8e6d-5c4b3a291807", Type: "InternetGateway", Name: "igw-batch"},
+	}
+
+	igws := routingtables.FilterGatewaysByType(gateways, routingtables.GatewayTypeInternetGateway)
+	th.AssertEquals(t, 2, len(igws))
+	th.AssertEquals(t, "igw-web", igws[0].Name)
+	th.AssertEquals(t, "igw-batch", igws[1].Name)
+
+	th.AssertEquals(t, 0, len(routingtables.FilterGatewaysByType(gateways, "vpngateway")))
+}
+
+func TestNameMetadata(t *testing.T) {
+	name, err := routingtables.EncodeNameMetadata("web-rt", map[string]string{"owner": "team-a", "env": "prod"})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "web-rt{env=prod,owner=team-a}", name)
+
+	rt := routingtables.RoutingTable{Name: name}
+	th.AssertDeepEquals(t, map[string]string{"owner": "team-a", "env": "prod"}, rt.Metadata())
+	base, _ := routingtables.DecodeNameMetadata(name)
+	th.AssertEquals(t, "web-rt", base)
+
+	name, err = routingtables.EncodeNameMetadata("web-rt", nil)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "web-rt", name)
+
+	base, metadata := routingtables.DecodeNameMetadata("legacy {rt}")
+	th.AssertEquals(t, "legacy {rt}", base)
+	if metadata != nil {
+		t.Fatalf("expected no metadata, got %v", metadata)
+	}
+
+	_, err = routingtables.EncodeNameMetadata("web-rt", map[string]string{"owner": "a,b"})
+	if _, ok := err.(gophercloud.ErrInvalidInput); !ok {
+		t.Fatalf("expected ErrInvalidInput, got %#v", err)
+	}
+
+	_, err = routingtables.EncodeNameMetadata("web-rt", map[string]string{"note": strings.Repeat("x", routingtables.MaxNameLength)})
+	invalid, ok := err.(gophercloud.ErrInvalidInput)
+	if !ok {
+		t.Fatalf("expected ErrInvalidInput, got %#v", err)
+	}
+	if !strings.Contains(invalid.Info, "is 268 characters long") {
+		t.Fatalf("expected the actual length in %q", invalid.Info)
+	}
+}
+
+func TestSortRoutes(t *testing.T) {
+	routes := []routingtables.Route{
+		{ID: "bad-b", CIDR: "not-a-cidr"},
+		{ID: "v6", CIDR: "2001:db8::/32"},
+		{ID: "wide", CIDR: "10.0.0.0/8"},
+		{ID: "high", CIDR: "192.168.1.0/24"},
+		{ID: "narrow", CIDR: "10.0.0.0/16"},
+		{ID: "low", CIDR: "9.255.0.0/16"},
+		{ID: "bad-a", CIDR: ""},
+		{ID: "dup-b", CIDR: "172.16.0.0/12"},
+		{ID: "dup-a", CIDR: "172.16.0.0/12"},
+	}
+
+	routingtables.SortRoutes(routes)
+
+	var ids []string
+	for _, route := range routes {
+		ids = append(ids, route.ID)
+	}
+	th.AssertDeepEquals(t, []string{"low", "wide", "narrow", "dup-a", "dup-b", "high", "v6", "bad-a", "bad-b"}, ids)
+}
+
+func TestString(t *testing.T) {
+	office := "office"
+	route := routingtables.Route{ID: "r1", CIDR: "192.168.10.0/24", Gateway: "10.0.0.10", RoutingTableID: "rt1", Description: &office}
+	th.AssertEquals(t, `Route{ID: r1, CIDR: 192.168.10.0/24, Gateway: 10.0.0.10, RoutingTable: rt1, Description: "office"}`, route.String())
+
+	route.Description = nil
+	th.AssertEquals(t, `Route{ID: r1, CIDR: 192.168.10.0/24, Gateway: 10.0.0.10, RoutingTable: rt1, Description: <nil>}`, fmt.Sprintf("%#v", route))
+
+	rt := &routingtables.RoutingTable{ID: "rt1", Name: "web-rt", State: "available", Routes: []routingtables.Route{route}}
+	th.AssertEquals(t, `RoutingTable{ID: rt1, Name: "web-rt", State: available, Default: false, Routes: 1}`, fmt.Sprint(rt))
+	th.AssertEquals(t, rt.String(), fmt.Sprintf("%#v", *rt))
+}
+
+func TestFilterRoutesByDescription(t *testing.T) {
+	teamA, teamB, empty := "owner=Team-A web", "owner=team-b", ""
+	routes := []routingtables.Route{
+		{ID: "r1", Description: &teamA},
+		{ID: "r2", Description: &teamB},
+		{ID: "r3"},
+		{ID: "r4", Description: &empty},
+	}
+
+	filtered := routingtables.FilterRoutesByDescription(routes, "OWNER=team-a")
+	th.AssertEquals(t, 1, len(filtered))
+	th.AssertEquals(t, "r1", filtered[0].ID)
+
+	th.AssertEquals(t, 3, len(routingtables.FilterRoutesByDescription(routes, "")))
+	th.AssertEquals(t, 0, len(routingtables.FilterRoutesByDescription(routes, "team-c")))
+}
+
+func TestFallbackEmptySlicesAreNotNil(t *testing.T) {
+	var body interface{}
+	err := json.Unmarshal([]byte(`{"routingtable": {"id": "5c1e2f3a-4b5c-4d6e-8f7a-9b0c1d2e3f4a", "distributed": "yes", "vpcs": [], "subnets": [], "routes": []}}`), &body)
+	th.AssertNoErr(t, err)
+	r := routingtables.RoutingTableResult{Result: gophercloud.Result{Body: body}}
+
+	// The invalid "distributed" forces the fallback parser.
+	rt, err := r.Extract()
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, true, rt.VPCs != nil && len(rt.VPCs) == 0)
+	th.AssertEquals(t, true, rt.Subnets != nil && len(rt.Subnets) == 0)
+	th.AssertEquals(t, true, rt.Routes != nil && len(rt.Routes) == 0)
+
+	b, err := json.Marshal(rt.Routes)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "[]", string(b))
+}
+
+func TestDiffRoutesEmptyResultsAreNotNil(t *testing.T) {
+	routes := []routingtables.Route{{ID: "r1", CIDR: "192.168.10.0/24", Gateway: "10.0.0.10"}}
+
+	toAdd, toDelete := routingtables.DiffRoutes(routes, routes)
+	th.AssertEquals(t, true, toAdd != nil && len(toAdd) == 0)
+	th.AssertEquals(t, true, toDelete != nil && len(toDelete) == 0)
+
+	toAdd, toDelete = routingtables.DiffRoutesWithDescription(nil, nil)
+	th.AssertEquals(t, true, toAdd != nil && len(toAdd) == 0)
+	th.AssertEquals(t, true, toDelete != nil && len(toDelete) == 0)
+}
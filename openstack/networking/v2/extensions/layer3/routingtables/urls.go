@@ -7,12 +7,44 @@
 
 package routingtables
 
-import "github.com/cloud-barista/nhncloud-sdk-go"
+import (
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+)
 
-const resourcePath = "routingtables"
+// ResourcePath is the path of the routing table resource, relative to the
+// resource base URL of the service client. Regions that expose routing tables
+// under a different name can override it; it must be set before any request
+// is made. A versioned path can also be targeted through the ResourceBase of
+// the service client.
+var ResourcePath = "routingtables"
+
+// RoutesResourcePath is the path of the route resource, relative to the
+// resource base URL of the service client. It can be overridden like
+// ResourcePath.
+var RoutesResourcePath = "routes"
+
+var testEndpoint internal.Endpoint
+
+// SetEndpointForTesting redirects every request of this package to endpoint,
+// e.g. the URL of a mock server taken from an environment variable by a
+// contract test. ResourcePath and RoutesResourcePath are appended to endpoint
+// in place of the resource base URL of the service client; authentication
+// still goes through the provider client. It returns a function restoring the
+// previous endpoint. An empty endpoint removes the override.
+//
+// It is meant for tests only and must not be used in production code.
+func SetEndpointForTesting(endpoint string) (restore func()) {
+	return testEndpoint.Set(endpoint)
+}
+
+// serviceURL is c.ServiceURL, honoring SetEndpointForTesting.
+func serviceURL(c *gophercloud.ServiceClient, parts ...string) string {
+	return testEndpoint.ServiceURL(c, parts...)
+}
 
 func rootURL(c *gophercloud.ServiceClient) string {
-	return c.ServiceURL(resourcePath)
+	return serviceURL(c, ResourcePath)
 }
 
 func listURL(c *gophercloud.ServiceClient) string {
@@ -24,21 +56,29 @@ func createURL(c *gophercloud.ServiceClient) string {
 }
 
 func resourceURL(c *gophercloud.ServiceClient, id string) string {
-	return c.ServiceURL(resourcePath, id)
+	return serviceURL(c, ResourcePath, id)
 }
 
 func attachGatewayURL(c *gophercloud.ServiceClient, id string) string {
-	return c.ServiceURL(resourcePath, id, "attach_gateway")
+	return serviceURL(c, ResourcePath, id, "attach_gateway")
 }
 
 func detachGatewayURL(c *gophercloud.ServiceClient, id string) string {
-	return c.ServiceURL(resourcePath, id, "detach_gateway")
+	return serviceURL(c, ResourcePath, id, "detach_gateway")
+}
+
+func attachSubnetURL(c *gophercloud.ServiceClient, id string) string {
+	return serviceURL(c, ResourcePath, id, "attach_subnet")
+}
+
+func detachSubnetURL(c *gophercloud.ServiceClient, id string) string {
+	return serviceURL(c, ResourcePath, id, "detach_subnet")
 }
 
 func setAsDefaultURL(c *gophercloud.ServiceClient, id string) string {
-	return c.ServiceURL(resourcePath, id, "set_as_default")
+	return serviceURL(c, ResourcePath, id, "set_as_default")
 }
 
 func relatedGatewaysURL(c *gophercloud.ServiceClient, id string) string {
-	return c.ServiceURL(resourcePath, id, "related_gateways")
+	return serviceURL(c, ResourcePath, id, "related_gateways")
 }
@@ -0,0 +1,1391 @@
+// Proof of Concepts of NHN Cloud SDK Go
+// NHN Cloud SDK Go is an SDK for developing NHN Cloud connection drivers that connect NHN Cloud to CB-Spider, a sub-framework of the Cloud-Barista multi-cloud project.
+//
+// * Cloud-Barista: https://github.com/cloud-barista
+//
+// Created by ETRI, 2026.10
+
+package routingtables
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/nhncloud-sdk-go"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internal"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/extensions/layer3/internetgateways"
+	"github.com/cloud-barista/nhncloud-sdk-go/openstack/networking/v2/vpcsubnets"
+	"github.com/cloud-barista/nhncloud-sdk-go/pagination"
+)
+
+// WaitForState will continually poll a routing table until it reaches the
+// target state. It returns immediately with an error if the routing table
+// enters the "error" state, and a gophercloud.ErrTimeOut if the target state
+// is not reached within the timeout.
+func WaitForState(c *gophercloud.ServiceClient, id string, target string, timeout time.Duration) error {
+	what := fmt.Sprintf("routing table [%s] to become [%s]", id, target)
+	return internal.WaitFor(timeout, what, func() (bool, error) {
+		current, err := Get(c, id).Extract()
+		if err != nil {
+			return false, err
+		}
+
+		if current.State == target {
+			return true, nil
+		}
+
+		if RoutingTableState(current.State) == StateError {
+			return false, fmt.Errorf("routing table [%s] entered error state while waiting for [%s]", id, target)
+		}
+
+		return false, nil
+	})
+}
+
+// AttachGatewayAndWait attaches an internet gateway to a routing table, then
+// polls until the routing table references the gateway and the gateway
+// reports the "available" state. It fails immediately if the gateway enters
+// the "error" state, and returns a gophercloud.ErrTimeOut if the attachment
+// does not converge within the timeout.
+func AttachGatewayAndWait(c *gophercloud.ServiceClient, routingtableID, gatewayID string, timeout time.Duration) (*RoutingTable, error) {
+	if err := AttachGateway(c, routingtableID, AttachGatewayOpts{GatewayID: gatewayID}).Err; err != nil {
+		return nil, err
+	}
+
+	var rt *RoutingTable
+	what := fmt.Sprintf("internet gateway [%s] to be attached to routing table [%s]", gatewayID, routingtableID)
+	err := internal.WaitFor(timeout, what, func() (bool, error) {
+		current, err := Get(c, routingtableID).Extract()
+		if err != nil {
+			return false, err
+		}
+		if current.GatewayID != gatewayID {
+			return false, nil
+		}
+
+		igw, err := internetgateways.Get(c, gatewayID).Extract()
+		if err != nil {
+			return false, err
+		}
+		switch internetgateways.InternetGatewayState(igw.State) {
+		case internetgateways.StateAvailable:
+			rt = current
+			return true, nil
+		case internetgateways.StateError:
+			return false, fmt.Errorf("internet gateway [%s] entered error state while attaching to routing table [%s]", gatewayID, routingtableID)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+// provisionTimeout bounds each wait of ProvisionInternetRoutingTable and
+// CloneRoutingTable when the context has no earlier deadline.
+const provisionTimeout = 5 * time.Minute
+
+// ProvisionInternetRoutingTable creates a routing table, attaches the internet
+// gateway to it and, if makeDefault is set, makes it the default routing table
+// of its VPC, waiting for each step to settle. An empty gatewayID skips the
+// attachment. If a step after the creation fails, the gateway is detached and
+// the routing table deleted; the error of the failed step is returned, or an
+// ErrRollbackFailed if the routing table could not be deleted.
+//
+// Each wait gives up after 5 minutes; use
+// ProvisionInternetRoutingTableWithContext to set a shorter deadline.
+func ProvisionInternetRoutingTable(c *gophercloud.ServiceClient, opts CreateOpts, gatewayID string, makeDefault bool) (*RoutingTable, error) {
+	return ProvisionInternetRoutingTableWithContext(context.Background(), c, opts, gatewayID, makeDefault)
+}
+
+// ProvisionInternetRoutingTableWithContext is the context-aware variant of
+// ProvisionInternetRoutingTable. The waits end at the deadline of the context,
+// if it comes before their 5 minutes. The rollback is not bound to the
+// context, so that a routing table is not left behind when it expires.
+func ProvisionInternetRoutingTableWithContext(ctx context.Context, c *gophercloud.ServiceClient, opts CreateOpts, gatewayID string, makeDefault bool) (*RoutingTable, error) {
+	client := withContext(ctx, c)
+	rt, err := Create(client, opts).Extract()
+	if err != nil {
+		return nil, err
+	}
+
+	err = provisionInternetRoutingTable(client, rt.ID, gatewayID, makeDefault)
+	if err != nil {
+		return nil, rollbackRoutingTable(c, rt.ID, err)
+	}
+
+	return Get(client, rt.ID).Extract()
+}
+
+func provisionInternetRoutingTable(c *gophercloud.ServiceClient, id, gatewayID string, makeDefault bool) error {
+	if err := WaitForState(c, id, string(StateAvailable), provisionWaitTimeout(c)); err != nil {
+		return err
+	}
+
+	if gatewayID != "" {
+		if _, err := AttachGatewayAndWait(c, id, gatewayID, provisionWaitTimeout(c)); err != nil {
+			return err
+		}
+	}
+
+	if makeDefault {
+		if _, err := SetAsDefault(c, id).Extract(); err != nil {
+			return err
+		}
+		if err := WaitForState(c, id, string(StateAvailable), provisionWaitTimeout(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// provisionWaitTimeout returns provisionTimeout, or the time left until the
+// deadline of the client context if it is shorter.
+func provisionWaitTimeout(c *gophercloud.ServiceClient) time.Duration {
+	timeout := provisionTimeout
+	if c.Context == nil {
+		return timeout
+	}
+	if deadline, ok := c.Context.Deadline(); ok {
+		if left := time.Until(deadline); left < timeout {
+			timeout = left
+		}
+	}
+	return timeout
+}
+
+// rollbackRoutingTable detaches the internet gateway of a routing table, if
+// any, and deletes it after cause made a workflow fail.
+func rollbackRoutingTable(c *gophercloud.ServiceClient, id string, cause error) error {
+	if rt, err := Get(c, id).Extract(); err == nil && rt.GatewayID != "" {
+		// A failed detach shows up as a failed delete below.
+		DetachGateway(c, id)
+	}
+	if err := Delete(c, id).ExtractErr(); err != nil {
+		return ErrRollbackFailed{RoutingTableID: id, Err: cause, RollbackErr: err}
+	}
+	return cause
+}
+
+// Ping checks that the networking endpoint is reachable and that the token of
+// the client is accepted, by requesting a single routing table. Authentication
+// failures are returned as the gophercloud errors describing them, such as
+// gophercloud.ErrDefault401 or *gophercloud.ErrUnableToReauthenticate, and
+// other HTTP errors as the matching gophercloud.ErrDefaultXXX. Any failure to
+// get an HTTP response at all is returned as an ErrEndpointUnreachable.
+func Ping(c *gophercloud.ServiceClient) error {
+	url := listURL(c) + "?limit=1"
+	_, err := c.Get(url, nil, &gophercloud.RequestOpts{
+		OkCodes: []int{200},
+	})
+	if err == nil {
+		return nil
+	}
+
+	var statusErr gophercloud.StatusCodeError
+	var reauthErr *gophercloud.ErrUnableToReauthenticate
+	var afterReauthErr *gophercloud.ErrErrorAfterReauthentication
+	if errors.As(err, &statusErr) || errors.As(err, &reauthErr) || errors.As(err, &afterReauthErr) {
+		return err
+	}
+	return ErrEndpointUnreachable{URL: url, Err: err}
+}
+
+// ListAll lists routing tables, following all pages, and returns them as a
+// single slice.
+//
+// If opts is a ListOpts with a VPCID, the listing is requested with Detail so
+// that the VPCs of each routing table are known, and the results are also
+// filtered client-side with FilterRoutingTablesByVPC, for regions that ignore
+// the vpc_id query parameter. Likewise, the results are filtered client-side
+// by IDs, ID included, for regions that ignore repeated id parameters.
+func ListAll(c *gophercloud.ServiceClient, opts ListOptsBuilder) ([]RoutingTable, error) {
+	var vpcID string
+	var ids []string
+	if listOpts, ok := opts.(ListOpts); ok {
+		vpcID, ids = listOpts.VPCID, listOpts.idSet()
+		if vpcID != "" {
+			detail := true
+			listOpts.Detail = &detail
+			opts = listOpts
+		}
+	}
+
+	allPages, err := List(c, opts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	tables, err := ExtractRoutingTables(allPages)
+	if err != nil {
+		return nil, err
+	}
+	if vpcID != "" {
+		tables = FilterRoutingTablesByVPC(tables, vpcID)
+	}
+	if len(ids) > 0 {
+		tables = filterRoutingTablesByID(tables, ids)
+	}
+	return tables, nil
+}
+
+// filterRoutingTablesByID returns the routing tables whose ID is one of ids.
+func filterRoutingTablesByID(tables []RoutingTable, ids []string) []RoutingTable {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	filtered := make([]RoutingTable, 0, len(ids))
+	for _, rt := range tables {
+		if wanted[rt.ID] {
+			filtered = append(filtered, rt)
+		}
+	}
+	return filtered
+}
+
+// ListRoutingTablesByGateway lists the routing tables the internet gateway is
+// attached to, following all pages. It is the inverse of GetRelatedGateways.
+func ListRoutingTablesByGateway(c *gophercloud.ServiceClient, gatewayID string) ([]RoutingTable, error) {
+	tables, err := ListAll(c, ListOpts{GatewayID: gatewayID})
+	if err != nil {
+		return nil, err
+	}
+
+	attached := make([]RoutingTable, 0)
+	for _, rt := range tables {
+		if rt.GatewayID == gatewayID {
+			attached = append(attached, rt)
+		}
+	}
+	return attached, nil
+}
+
+// ListAllRoutes lists routes, following all pages, and returns them as a
+// single slice.
+func ListAllRoutes(c *gophercloud.ServiceClient, opts RouteListOptsBuilder) ([]Route, error) {
+	allPages, err := ListRoutes(c, opts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	return ExtractRoutes(allPages)
+}
+
+// ListAllRoutesConcurrently is the same as ListAllRoutes, but fetches the
+// pages using at most concurrency parallel requests when the API makes it
+// possible: the first page must report the size of the collection in an
+// X-Total-Count header and link to the next page by number, through a page
+// query parameter. The remaining page URLs are then derived from the next
+// link and the size of the first page. Otherwise the pages are fetched one
+// after the other by following the next links. Routes are returned in page
+// order either way.
+//
+// Pages are not fetched atomically: routes created or deleted during the
+// listing may be missed or returned twice.
+func ListAllRoutesConcurrently(c *gophercloud.ServiceClient, opts RouteListOptsBuilder, concurrency int) ([]Route, error) {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	var (
+		routes = []Route{}
+		next   string
+		total  int
+		counts bool
+	)
+	err := ListRoutes(c, opts).EachPage(func(page pagination.Page) (bool, error) {
+		routePage := page.(RoutePage)
+		var err error
+		if routes, err = ExtractRoutes(routePage); err != nil {
+			return false, err
+		}
+		if next, err = routePage.NextPageURL(); err != nil {
+			return false, err
+		}
+		total, counts = routePage.TotalCount()
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if next == "" {
+		return routes, nil
+	}
+
+	urls, ok := numberedPageURLs(next, total, len(routes))
+	if !counts || !ok {
+		allPages, err := newRoutePager(c, next).AllPages()
+		if err != nil {
+			return nil, err
+		}
+		rest, err := ExtractRoutes(allPages)
+		if err != nil {
+			return nil, err
+		}
+		return append(routes, rest...), nil
+	}
+
+	pages := make([][]Route, len(urls))
+	errs := make([]error, len(urls))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(urls); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				pages[i], errs[i] = fetchRoutePage(c, urls[i])
+			}
+		}()
+	}
+	for i := range urls {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := range urls {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		routes = append(routes, pages[i]...)
+	}
+	return routes, nil
+}
+
+// numberedPageURLs returns the URLs of the pages from next to the last one,
+// given the size of the collection and of a page. ok is false if next does
+// not designate its page by number.
+func numberedPageURLs(next string, total, pageSize int) (urls []string, ok bool) {
+	u, err := url.Parse(next)
+	if err != nil || pageSize <= 0 {
+		return nil, false
+	}
+	q := u.Query()
+	first, err := strconv.Atoi(q.Get("page"))
+	if err != nil || first < 1 {
+		return nil, false
+	}
+
+	last := (total + pageSize - 1) / pageSize
+	for n := first; n <= last; n++ {
+		q.Set("page", strconv.Itoa(n))
+		u.RawQuery = q.Encode()
+		urls = append(urls, u.String())
+	}
+	return urls, true
+}
+
+// fetchRoutePage returns the routes of the single page at pageURL.
+func fetchRoutePage(c *gophercloud.ServiceClient, pageURL string) ([]Route, error) {
+	var routes []Route
+	err := newRoutePager(c, pageURL).EachPage(func(page pagination.Page) (bool, error) {
+		var err error
+		routes, err = ExtractRoutes(page)
+		return false, err
+	})
+	return routes, err
+}
+
+// ListRoutesWithTableNames lists every route and annotates it with the name
+// of its routing table. Routing tables are listed once, whatever the number
+// of routes.
+func ListRoutesWithTableNames(c *gophercloud.ServiceClient) ([]RouteWithTable, error) {
+	routes, err := ListAllRoutes(c, RouteListOpts{})
+	if err != nil {
+		return nil, err
+	}
+	tables, err := ListAll(c, ListOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(tables))
+	for _, rt := range tables {
+		names[rt.ID] = rt.Name
+	}
+
+	annotated := make([]RouteWithTable, 0, len(routes))
+	for _, route := range routes {
+		annotated = append(annotated, RouteWithTable{Route: route, RoutingTableName: names[route.RoutingTableID]})
+	}
+	return annotated, nil
+}
+
+// EachRoute lists routes page by page and calls fn for each of them, without
+// holding more than one page in memory. It stops at the first error returned
+// by fn and returns it.
+func EachRoute(c *gophercloud.ServiceClient, opts RouteListOptsBuilder, fn func(Route) error) error {
+	return ListRoutes(c, opts).EachPage(func(page pagination.Page) (bool, error) {
+		routes, err := ExtractRoutes(page)
+		if err != nil {
+			return false, err
+		}
+		for _, route := range routes {
+			if err := fn(route); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// DetailedList lists routing tables with Detail enabled, following all pages,
+// and makes sure every associated subnet has its name filled in. Even with
+// Detail, the API may return subnets as bare ID strings; the names of those
+// are resolved with a single subnet List request.
+func DetailedList(c *gophercloud.ServiceClient, opts ListOpts) ([]RoutingTable, error) {
+	detail := true
+	opts.Detail = &detail
+	tables, err := ListAll(c, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved := false
+	for _, rt := range tables {
+		for _, subnet := range rt.Subnets {
+			if subnet.Name == "" {
+				unresolved = true
+			}
+		}
+	}
+	if !unresolved {
+		return tables, nil
+	}
+
+	allPages, err := vpcsubnets.List(c, nil).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	subnets, err := vpcsubnets.ExtractVpcsubnets(allPages)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(subnets))
+	for _, subnet := range subnets {
+		names[subnet.ID] = subnet.Name
+	}
+
+	for i := range tables {
+		for j, subnet := range tables[i].Subnets {
+			if subnet.Name == "" {
+				tables[i].Subnets[j].Name = names[subnet.ID]
+			}
+		}
+	}
+	return tables, nil
+}
+
+// GetRoutesForTable lists the routes of a routing table, following all pages,
+// and returns them in the order of SortRoutes.
+func GetRoutesForTable(c *gophercloud.ServiceClient, routingtableID string) ([]Route, error) {
+	routes, err := ListAllRoutes(c, RouteListOpts{RoutingTableID: routingtableID})
+	if err != nil {
+		return nil, err
+	}
+
+	SortRoutes(routes)
+	return routes, nil
+}
+
+// SortRoutes sorts routes in place by network address, then by prefix
+// length, so that route sets can be compared. IPv4 routes come before IPv6
+// ones, and routes whose CIDR cannot be parsed come last, ordered by CIDR.
+// Ties are broken by ID, which makes the order deterministic.
+func SortRoutes(routes []Route) {
+	keys := make([]routeSortKey, len(routes))
+	for i, route := range routes {
+		keys[i] = newRouteSortKey(route)
+	}
+	sort.Sort(routesByNetwork{routes: routes, keys: keys})
+}
+
+// routeSortKey is the parsed CIDR a route is sorted by.
+type routeSortKey struct {
+	valid   bool
+	family  int
+	network []byte
+	prefix  int
+}
+
+func newRouteSortKey(route Route) routeSortKey {
+	_, ipNet, err := net.ParseCIDR(route.CIDR)
+	if err != nil {
+		return routeSortKey{}
+	}
+
+	key := routeSortKey{valid: true, family: 6, network: ipNet.IP.To16()}
+	if ip4 := ipNet.IP.To4(); ip4 != nil {
+		key.family, key.network = 4, ip4
+	}
+	key.prefix, _ = ipNet.Mask.Size()
+	return key
+}
+
+// routesByNetwork implements sort.Interface for SortRoutes, keeping the
+// parsed keys next to the routes.
+type routesByNetwork struct {
+	routes []Route
+	keys   []routeSortKey
+}
+
+func (s routesByNetwork) Len() int { return len(s.routes) }
+
+func (s routesByNetwork) Swap(i, j int) {
+	s.routes[i], s.routes[j] = s.routes[j], s.routes[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+func (s routesByNetwork) Less(i, j int) bool {
+	a, b := s.keys[i], s.keys[j]
+	if a.valid != b.valid {
+		return a.valid
+	}
+	if a.valid {
+		if a.family != b.family {
+			return a.family < b.family
+		}
+		if c := bytes.Compare(a.network, b.network); c != 0 {
+			return c < 0
+		}
+		if a.prefix != b.prefix {
+			return a.prefix < b.prefix
+		}
+	}
+	if s.routes[i].CIDR != s.routes[j].CIDR {
+		return s.routes[i].CIDR < s.routes[j].CIDR
+	}
+	return s.routes[i].ID < s.routes[j].ID
+}
+
+// RouteChange describes what ReplaceRoute did to reach the requested route.
+type RouteChange string
+
+const (
+	// RouteCreated indicates that no route with the CIDR existed and one was created.
+	RouteCreated RouteChange = "created"
+
+	// RouteUpdated indicates that the existing route was updated.
+	RouteUpdated RouteChange = "updated"
+
+	// RouteUnchanged indicates that the existing route already matched.
+	RouteUnchanged RouteChange = "unchanged"
+)
+
+// CreateRouteAndGetTable creates a route and then retrieves its routing table,
+// which already lists the new route. If the routing table cannot be
+// retrieved, the created route is still returned along with the error. The
+// routing table of opts is retrieved if the response does not name one, and
+// the returned route is then nil if the response does not hold it either.
+func CreateRouteAndGetTable(c *gophercloud.ServiceClient, opts CreateRouteOpts) (*RoutingTable, *Route, error) {
+	route, err := CreateRoute(c, opts).Extract()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	routingtableID := opts.RoutingTableID
+	if route != nil && route.RoutingTableID != "" {
+		routingtableID = route.RoutingTableID
+	}
+	rt, err := Get(c, routingtableID).Extract()
+	if err != nil {
+		return nil, route, err
+	}
+	return rt, route, nil
+}
+
+// CreateRouteOrGet creates a route, or returns the existing route of the
+// routing table to the same CIDR if the API refuses the creation with a 409
+// Conflict; created reports whether the route was created. The existing route
+// may go through another gateway than the requested one. If no route to the
+// CIDR is found after a conflict, the conflict error is returned.
+func CreateRouteOrGet(c *gophercloud.ServiceClient, opts CreateRouteOpts) (route *Route, created bool, err error) {
+	route, err = CreateRoute(c, opts).Extract()
+	if err == nil {
+		return route, true, nil
+	}
+	if _, ok := err.(gophercloud.ErrDefault409); !ok {
+		return nil, false, err
+	}
+
+	routes, listErr := ListAllRoutes(c, RouteListOpts{RoutingTableID: opts.RoutingTableID, CIDR: opts.CIDR})
+	if listErr != nil {
+		return nil, false, listErr
+	}
+	want := Route{CIDR: opts.CIDR}
+	wantCIDR, _ := want.NormalizedCIDR()
+	for i := range routes {
+		got, normErr := routes[i].NormalizedCIDR()
+		if normErr == nil && got == wantCIDR && routes[i].RoutingTableID == opts.RoutingTableID {
+			return &routes[i], false, nil
+		}
+	}
+	return nil, false, err
+}
+
+// ReplaceRoute makes sure the routing table has a route to cidr through
+// gateway with the given description. An existing route with the same CIDR is
+// updated if its gateway or description differs; otherwise a new route is
+// created. It returns the resulting route and what was changed.
+//
+// CIDRs are compared in their normalized form, so that "10.0.0.1/24" matches
+// a route to "10.0.0.0/24"; a new route is created with the normalized CIDR.
+func ReplaceRoute(c *gophercloud.ServiceClient, routingtableID, cidr, gateway, description string) (*Route, RouteChange, error) {
+	want := Route{CIDR: cidr}
+	if normalized, err := want.NormalizedCIDR(); err == nil {
+		cidr = normalized
+	}
+	routes, err := ListAllRoutes(c, RouteListOpts{RoutingTableID: routingtableID, CIDR: cidr})
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, existing := range routes {
+		existingCIDR, err := existing.NormalizedCIDR()
+		if err != nil || existingCIDR != cidr || existing.RoutingTableID != routingtableID {
+			continue
+		}
+		if existing.Gateway == gateway && routeDescription(existing) == description {
+			return &existing, RouteUnchanged, nil
+		}
+
+		route, err := UpdateRoute(c, existing.ID, UpdateRouteOpts{
+			Gateway:     gateway,
+			Description: &description,
+		}).Extract()
+		if err != nil {
+			return nil, "", err
+		}
+		return route, RouteUpdated, nil
+	}
+
+	route, err := CreateRoute(c, CreateRouteOpts{
+		RoutingTableID: routingtableID,
+		CIDR:           cidr,
+		Gateway:        gateway,
+		Description:    description,
+	}).Extract()
+	if err != nil {
+		return nil, "", err
+	}
+	return route, RouteCreated, nil
+}
+
+// GetRouteInTable retrieves a route and checks that it belongs to the given
+// routing table. It returns an ErrRouteNotInTable if it does not, so that a
+// route ID mixed up between routing tables is not acted upon.
+func GetRouteInTable(c *gophercloud.ServiceClient, routingtableID, routeID string) (*Route, error) {
+	route, err := GetRoute(c, routeID).Extract()
+	if err != nil {
+		return nil, err
+	}
+	if route.RoutingTableID != routingtableID {
+		return nil, ErrRouteNotInTable{RouteID: routeID, RoutingTableID: routingtableID, ActualRoutingTableID: route.RoutingTableID}
+	}
+	return route, nil
+}
+
+// MoveRoute moves a route to another routing table by creating an equivalent
+// route in the target routing table and deleting the original one. If the
+// original cannot be deleted, the new route is deleted again and the delete
+// error returned; if that fails too, an ErrRouteDuplicated reports both
+// routes. System-managed routes cannot be moved and are reported as
+// ErrDefaultRoute.
+func MoveRoute(c *gophercloud.ServiceClient, routeID, targetRoutingTableID string) (*Route, error) {
+	route, err := GetRoute(c, routeID).Extract()
+	if err != nil {
+		return nil, err
+	}
+	if route.RoutingTableID == targetRoutingTableID {
+		return route, nil
+	}
+	if route.IsDefault() {
+		return nil, ErrDefaultRoute{RouteID: routeID, CIDR: route.CIDR}
+	}
+
+	cidr, err := route.NormalizedCIDR()
+	if err != nil {
+		return nil, err
+	}
+	moved, err := CreateRoute(c, CreateRouteOpts{
+		RoutingTableID: targetRoutingTableID,
+		CIDR:           cidr,
+		Gateway:        route.Gateway,
+		Description:    routeDescription(*route),
+	}).Extract()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := DeleteRoute(c, routeID).ExtractErr(); err != nil {
+		if rollbackErr := DeleteRoute(c, moved.ID).ExtractErr(); rollbackErr != nil {
+			return nil, ErrRouteDuplicated{RouteID: routeID, CopyID: moved.ID, Err: err, RollbackErr: rollbackErr}
+		}
+		return nil, err
+	}
+	return moved, nil
+}
+
+// GetDefaultRoutingTable returns the default routing table of a VPC. It
+// returns a gophercloud.ErrResourceNotFound if the VPC has no default routing
+// table and a gophercloud.ErrMultipleResourcesFound if it has more than one.
+func GetDefaultRoutingTable(c *gophercloud.ServiceClient, vpcID string) (*RoutingTable, error) {
+	defaultTable := true
+	detail := true
+	allTables, err := ListAll(c, ListOpts{DefaultTable: &defaultTable, Detail: &detail})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []RoutingTable
+	for _, rt := range allTables {
+		if rt.DefaultTable && belongsToVPC(rt, vpcID) {
+			matches = append(matches, rt)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		err := gophercloud.ErrResourceNotFound{}
+		err.ResourceType = "routing table"
+		err.Name = vpcID
+		err.Info = fmt.Sprintf("Unable to find a default routing table for VPC %s", vpcID)
+		return nil, err
+	case 1:
+		return &matches[0], nil
+	default:
+		err := gophercloud.ErrMultipleResourcesFound{}
+		err.ResourceType = "routing table"
+		err.Name = vpcID
+		err.Count = len(matches)
+		err.Info = fmt.Sprintf("Found %d default routing tables for VPC %s", len(matches), vpcID)
+		return nil, err
+	}
+}
+
+// FindRoutingTableByName returns the routing table of a VPC with exactly the
+// given name. Names are not guaranteed to be unique, so a name matching more
+// than one routing table yields an ErrMultipleRoutingTablesFound listing the
+// matching IDs. An empty vpcID searches across all VPCs.
+func FindRoutingTableByName(c *gophercloud.ServiceClient, vpcID, name string) (*RoutingTable, error) {
+	detail := true
+	allTables, err := ListAll(c, ListOpts{Name: name, Detail: &detail})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []RoutingTable
+	for _, rt := range allTables {
+		if rt.Name == name && (vpcID == "" || belongsToVPC(rt, vpcID)) {
+			matches = append(matches, rt)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		err := gophercloud.ErrResourceNotFound{}
+		err.ResourceType = "routing table"
+		err.Name = name
+		return nil, err
+	case 1:
+		return &matches[0], nil
+	default:
+		err := ErrMultipleRoutingTablesFound{}
+		err.ResourceType = "routing table"
+		err.Name = name
+		err.Count = len(matches)
+		for _, rt := range matches {
+			err.IDs = append(err.IDs, rt.ID)
+		}
+		return nil, err
+	}
+}
+
+// CreateRoutingTableIfNotExists returns the routing table of the VPC named
+// opts.Name, creating it if there is none; created reports whether it was
+// created. A 409 Conflict answered to the creation is taken for a concurrent
+// creation of the same routing table, which is then looked up again, so that
+// provisioning can safely be retried.
+func CreateRoutingTableIfNotExists(c *gophercloud.ServiceClient, opts CreateOpts) (rt *RoutingTable, created bool, err error) {
+	if err := opts.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	rt, err = FindRoutingTableByName(c, opts.VPCID, opts.Name)
+	if _, ok := err.(gophercloud.ErrResourceNotFound); !ok {
+		return rt, false, err
+	}
+
+	rt, err = Create(c, opts).Extract()
+	if err == nil {
+		return rt, true, nil
+	}
+	if _, ok := err.(gophercloud.ErrDefault409); !ok {
+		return nil, false, err
+	}
+
+	existing, findErr := FindRoutingTableByName(c, opts.VPCID, opts.Name)
+	if _, ok := findErr.(gophercloud.ErrResourceNotFound); ok {
+		return nil, false, err
+	}
+	return existing, false, findErr
+}
+
+// belongsToVPC reports whether the routing table is part of the given VPC.
+func belongsToVPC(rt RoutingTable, vpcID string) bool {
+	for _, id := range rt.GetVPCIDs() {
+		if id == vpcID {
+			return true
+		}
+	}
+	return false
+}
+
+// DetachAllSubnets disassociates every subnet currently associated with a
+// routing table. A failure to detach one subnet does not stop the others from
+// being detached; all failures are reported together in an ErrDetachSubnets.
+func DetachAllSubnets(c *gophercloud.ServiceClient, routingtableID string) error {
+	rt, err := Get(c, routingtableID).Extract()
+	if err != nil {
+		return err
+	}
+
+	failed := ErrDetachSubnets{RoutingTableID: routingtableID}
+	for _, subnetID := range rt.GetSubnetIDs() {
+		if err := DisassociateSubnet(c, routingtableID, subnetID).Err; err != nil {
+			if failed.Failures == nil {
+				failed.Failures = make(map[string]error)
+			}
+			failed.SubnetIDs = append(failed.SubnetIDs, subnetID)
+			failed.Failures[subnetID] = err
+		}
+	}
+
+	if len(failed.SubnetIDs) > 0 {
+		return failed
+	}
+	return nil
+}
+
+// ResetRoutes deletes every route of a routing table except the
+// system-managed ones (see Route.IsDefault), bringing it back to its
+// baseline. A failure to delete one route does not stop the others from
+// being deleted; all failures are reported together in an ErrResetRoutes.
+// deleted is the number of routes that were deleted.
+func ResetRoutes(c *gophercloud.ServiceClient, routingtableID string) (deleted int, err error) {
+	routes, err := ListAllRoutes(c, RouteListOpts{RoutingTableID: routingtableID})
+	if err != nil {
+		return 0, err
+	}
+
+	failed := ErrResetRoutes{RoutingTableID: routingtableID}
+	for _, route := range routes {
+		// Guard against a region ignoring the routingtable_id filter.
+		if route.IsDefault() || route.RoutingTableID != routingtableID {
+			continue
+		}
+		if err := DeleteRouteIfExists(c, route.ID).ExtractErr(); err != nil {
+			if failed.Failures == nil {
+				failed.Failures = make(map[string]error)
+			}
+			failed.RouteIDs = append(failed.RouteIDs, route.ID)
+			failed.Failures[route.ID] = err
+			continue
+		}
+		deleted++
+	}
+
+	if len(failed.RouteIDs) > 0 {
+		return deleted, failed
+	}
+	return deleted, nil
+}
+
+// defaultConcurrency is the number of workers used by CreateRoutesConcurrently
+// when the caller does not ask for a positive number.
+const defaultConcurrency = 4
+
+// CreateRoutesConcurrently creates the routes using at most concurrency
+// parallel requests. The created routes are returned in input order; routes
+// that failed are left out and reported together in an ErrCreateRoutes.
+func CreateRoutesConcurrently(c *gophercloud.ServiceClient, opts []CreateRouteOpts, concurrency int) ([]Route, error) {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	routes := make([]*Route, len(opts))
+	errs := make([]error, len(opts))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(opts); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				routes[i], errs[i] = CreateRoute(c, opts[i]).Extract()
+			}
+		}()
+	}
+	for i := range opts {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	created := make([]Route, 0, len(opts))
+	var failed ErrCreateRoutes
+	for i := range opts {
+		if errs[i] != nil {
+			failed.Failed = append(failed.Failed, opts[i])
+			failed.Errors = append(failed.Errors, errs[i])
+			continue
+		}
+		if routes[i] != nil {
+			created = append(created, *routes[i])
+		}
+	}
+
+	if len(failed.Errors) > 0 {
+		return created, failed
+	}
+	return created, nil
+}
+
+// GetMany retrieves the routing tables with the given IDs, running at most
+// defaultConcurrency requests at a time. Routing tables that were retrieved
+// are returned keyed by ID; failures are returned keyed by ID in the second
+// map, which is empty if every Get succeeded. A routing table that does not
+// exist is reported as an ErrRoutingTableNotFound. Duplicate IDs are fetched
+// once.
+func GetMany(c *gophercloud.ServiceClient, ids []string) (map[string]*RoutingTable, map[string]error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	tables := make([]*RoutingTable, len(unique))
+	errs := make([]error, len(unique))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < defaultConcurrency && w < len(unique); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				tables[i], errs[i] = Get(c, unique[i]).Extract()
+			}
+		}()
+	}
+	for i := range unique {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	found := make(map[string]*RoutingTable, len(unique))
+	failed := make(map[string]error)
+	for i, id := range unique {
+		if errs[i] != nil {
+			failed[id] = errs[i]
+			continue
+		}
+		found[id] = tables[i]
+	}
+	return found, failed
+}
+
+// DeleteRoutes deletes the routes with the given IDs, running at most
+// defaultConcurrency requests at a time. A failure to delete one route does
+// not stop the others from being deleted. deleted lists the IDs of the routes
+// that were deleted, in input order; failures are returned keyed by ID in
+// errs, which is empty if every deletion succeeded. Duplicate IDs are deleted
+// once.
+func DeleteRoutes(c *gophercloud.ServiceClient, routeIDs []string) (deleted []string, errs map[string]error) {
+	unique := make([]string, 0, len(routeIDs))
+	seen := make(map[string]bool, len(routeIDs))
+	for _, id := range routeIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	results := make([]error, len(unique))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < defaultConcurrency && w < len(unique); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = DeleteRoute(c, unique[i]).ExtractErr()
+			}
+		}()
+	}
+	for i := range unique {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	deleted = make([]string, 0, len(unique))
+	errs = make(map[string]error)
+	for i, id := range unique {
+		if results[i] != nil {
+			errs[id] = results[i]
+			continue
+		}
+		deleted = append(deleted, id)
+	}
+	return deleted, errs
+}
+
+// CloneRoutingTable creates a routing table named newName in the given VPC,
+// with the same routing type as the source routing table, and copies the
+// routes of the source into it, with their CIDR in canonical notation (see
+// Route.NormalizedCIDR). System-managed routes (see Route.IsDefault) and
+// routes without a gateway IP cannot be created manually; they are not copied
+// and are returned as skipped, as are routes with an invalid CIDR and routes
+// duplicating an earlier one once normalized. skipped is empty but never nil
+// if every route was copied.
+//
+// Routes are only copied once the new routing table is available, which is
+// awaited for up to 5 minutes. If the wait or copying a route fails, the
+// routing table created so far is returned along with the error so the
+// caller can inspect or delete it.
+func CloneRoutingTable(c *gophercloud.ServiceClient, sourceID, newName, vpcID string) (*RoutingTable, []Route, error) {
+	source, err := Get(c, sourceID).Extract()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	distributed := source.Distributed
+	clone, err := Create(c, CreateOpts{Name: newName, VPCID: vpcID, Distributed: &distributed}).Extract()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var toCreate []CreateRouteOpts
+	skipped := make([]Route, 0)
+	seen := make(map[string]bool, len(source.Routes))
+	for _, r := range source.Routes {
+		if r.IsDefault() || r.Gateway == "" {
+			skipped = append(skipped, r)
+			continue
+		}
+		cidr, err := r.NormalizedCIDR()
+		if err != nil || seen[cidr+"|"+r.Gateway] {
+			skipped = append(skipped, r)
+			continue
+		}
+		seen[cidr+"|"+r.Gateway] = true
+		toCreate = append(toCreate, CreateRouteOpts{
+			CIDR:        cidr,
+			Gateway:     r.Gateway,
+			Description: routeDescription(r),
+		})
+	}
+
+	if err := WaitForState(c, clone.ID, string(StateAvailable), provisionWaitTimeout(c)); err != nil {
+		return clone, skipped, err
+	}
+	if _, err := createRoutesSequentially(c, clone.ID, toCreate); err != nil {
+		return clone, skipped, err
+	}
+
+	current, err := Get(c, clone.ID).Extract()
+	if err != nil {
+		return clone, skipped, err
+	}
+	return current, skipped, nil
+}
+
+// FilterRoutingTablesByTime returns the routing tables created at or after
+// after and strictly before before. A zero bound leaves that side of the
+// range open; tables without a creation time only match a fully open range.
+func FilterRoutingTablesByTime(tables []RoutingTable, after, before time.Time) []RoutingTable {
+	filtered := make([]RoutingTable, 0)
+	for _, rt := range tables {
+		created := rt.CreateTime.Time
+		if !after.IsZero() && (created.IsZero() || created.Before(after)) {
+			continue
+		}
+		if !before.IsZero() && (created.IsZero() || !created.Before(before)) {
+			continue
+		}
+		filtered = append(filtered, rt)
+	}
+	return filtered
+}
+
+// FilterGatewaysByType returns the gateways of the given type, such as
+// GatewayTypeInternetGateway. Types are compared case-insensitively.
+func FilterGatewaysByType(gateways []Gateway, gatewayType string) []Gateway {
+	filtered := make([]Gateway, 0)
+	for _, gw := range gateways {
+		if strings.EqualFold(gw.Type, gatewayType) {
+			filtered = append(filtered, gw)
+		}
+	}
+	return filtered
+}
+
+// FilterRoutingTablesByVPC returns the routing tables that belong to the VPC
+// with the given ID. Routing tables are only known to belong to a VPC if they
+// were listed with Detail.
+func FilterRoutingTablesByVPC(tables []RoutingTable, vpcID string) []RoutingTable {
+	filtered := make([]RoutingTable, 0)
+	for _, rt := range tables {
+		if belongsToVPC(rt, vpcID) {
+			filtered = append(filtered, rt)
+		}
+	}
+	return filtered
+}
+
+// FilterRoutesByDescription returns the routes whose description contains
+// substr, compared case-insensitively. Routes without a description never
+// match, even an empty substr.
+func FilterRoutesByDescription(routes []Route, substr string) []Route {
+	substr = strings.ToLower(substr)
+	filtered := make([]Route, 0)
+	for _, route := range routes {
+		if route.Description != nil && strings.Contains(strings.ToLower(*route.Description), substr) {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered
+}
+
+// DiffRoutes compares the current routes of a routing table with the desired
+// ones and returns the options to create the missing routes and the IDs of
+// the extra routes to delete. Routes are matched by CIDR and Gateway; the
+// Description is ignored (see DiffRoutesWithDescription). System-managed
+// routes (see Route.IsDefault) are never scheduled for deletion. Both slices
+// are empty but never nil if there is nothing to do.
+func DiffRoutes(current, desired []Route) (toAdd []CreateRouteOpts, toDelete []string) {
+	return diffRoutes(current, desired, false)
+}
+
+// DiffRoutesWithDescription is the same as DiffRoutes, but also requires the
+// Description of two routes to match for them to be considered equal.
+func DiffRoutesWithDescription(current, desired []Route) (toAdd []CreateRouteOpts, toDelete []string) {
+	return diffRoutes(current, desired, true)
+}
+
+func diffRoutes(current, desired []Route, compareDescription bool) (toAdd []CreateRouteOpts, toDelete []string) {
+	toAdd = make([]CreateRouteOpts, 0)
+	toDelete = make([]string, 0)
+	key := func(r Route) string {
+		k := r.CIDR + "|" + r.Gateway
+		if compareDescription {
+			k += "|" + routeDescription(r)
+		}
+		return k
+	}
+
+	currentKeys := make(map[string]bool, len(current))
+	for _, r := range current {
+		currentKeys[key(r)] = true
+	}
+
+	desiredKeys := make(map[string]bool, len(desired))
+	for _, r := range desired {
+		k := key(r)
+		if desiredKeys[k] {
+			continue
+		}
+		desiredKeys[k] = true
+
+		if !currentKeys[k] {
+			toAdd = append(toAdd, CreateRouteOpts{
+				RoutingTableID: r.RoutingTableID,
+				CIDR:           r.CIDR,
+				Gateway:        r.Gateway,
+				Description:    routeDescription(r),
+			})
+		}
+	}
+
+	for _, r := range current {
+		if !r.IsDefault() && !desiredKeys[key(r)] {
+			toDelete = append(toDelete, r.ID)
+		}
+	}
+
+	return toAdd, toDelete
+}
+
+// routeDescription returns the description of a route, or "" if it has none.
+func routeDescription(r Route) string {
+	if r.Description == nil {
+		return ""
+	}
+	return *r.Description
+}
+
+// RetryOpts configures how an operation is retried.
+type RetryOpts struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Defaults to 5.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. It doubles after
+	// each retry. Defaults to 1 second.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts. Defaults to 30 seconds.
+	MaxBackoff time.Duration
+}
+
+func (opts RetryOpts) withDefaults() RetryOpts {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = 1 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Second
+	}
+	return opts
+}
+
+// PreviewUpdate reports what Update would change on a routing table without
+// changing it: the options are validated as Update would, the routing table
+// is retrieved and the fields of opts that differ from its current values are
+// returned, keyed by their name in the API ("name", "distributed") with the
+// new value. Unset fields, such as a nil Distributed, are left unchanged and
+// never reported. The map is empty if the update would change nothing.
+func PreviewUpdate(c *gophercloud.ServiceClient, id string, opts UpdateOpts) (changes map[string]interface{}, err error) {
+	if _, err := opts.ToRoutingTableUpdateMap(); err != nil {
+		return nil, err
+	}
+	rt, err := Get(c, id).Extract()
+	if err != nil {
+		return nil, err
+	}
+
+	changes = make(map[string]interface{})
+	if opts.Name != "" && opts.Name != rt.Name {
+		changes["name"] = opts.Name
+	}
+	if opts.Distributed != nil && *opts.Distributed != rt.Distributed {
+		changes["distributed"] = *opts.Distributed
+	}
+	return changes, nil
+}
+
+// UpdateRoutingTableCAS updates a routing table with optimistic concurrency.
+// The routing table API has no ETag or revision to send in an If-Match
+// header, so the update is done as a read-modify-write: mutate is given the
+// current routing table and returns the options of the update, or nil options
+// if there is nothing to change. The routing table is read again right before
+// the update; if it changed in the meantime, or if the API answers 409
+// Conflict, the whole cycle is retried according to retry. This narrows the
+// window for a lost update without closing it. ErrUpdateConflict is returned
+// once the attempts are exhausted.
+func UpdateRoutingTableCAS(c *gophercloud.ServiceClient, id string, mutate func(current RoutingTable) (UpdateOptsBuilder, error), retry RetryOpts) (*RoutingTable, error) {
+	retry = retry.withDefaults()
+	backoff := retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		current, err := Get(c, id).Extract()
+		if err != nil {
+			return nil, err
+		}
+		opts, err := mutate(*current)
+		if err != nil || opts == nil {
+			return current, err
+		}
+
+		check, err := Get(c, id).Extract()
+		if err != nil {
+			return nil, err
+		}
+		if sameRevision(*current, *check) {
+			updated, err := Update(c, id, opts).Extract()
+			if _, ok := err.(gophercloud.ErrDefault409); !ok {
+				return updated, err
+			}
+		}
+
+		if attempt >= retry.MaxAttempts {
+			return nil, ErrUpdateConflict{ID: id, Attempts: attempt}
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
+// sameRevision reports whether two reads of a routing table observed the same
+// version of it.
+func sameRevision(a, b RoutingTable) bool {
+	return a.UpdateTime.Equal(b.UpdateTime.Time) &&
+		a.Name == b.Name &&
+		a.Distributed == b.Distributed &&
+		a.DefaultTable == b.DefaultTable &&
+		a.GatewayID == b.GatewayID
+}
+
+// AttachGatewayWithRetry is the same as AttachGateway, but retries with
+// exponential backoff while the API answers 409 Conflict, which happens when
+// the gateway is still finishing a previous detach. Meanwhile the gateway may
+// still reference another routing table, so a conflict is only reported as an
+// ErrGatewayAlreadyAttached once the last attempt failed. The request options
+// apply to each attempt.
+func AttachGatewayWithRetry(c *gophercloud.ServiceClient, routingtableID string, opts AttachGatewayOptsBuilder, retry RetryOpts, reqOpts ...RequestOption) (r AttachGatewayResult) {
+	return AttachGatewayWithRetryWithContext(context.Background(), c, routingtableID, opts, retry, reqOpts...)
+}
+
+// AttachGatewayWithRetryWithContext is the context-aware variant of
+// AttachGatewayWithRetry. It stops waiting for the next attempt when the
+// context is done, and returns the error of the context.
+func AttachGatewayWithRetryWithContext(ctx context.Context, c *gophercloud.ServiceClient, routingtableID string, opts AttachGatewayOptsBuilder, retry RetryOpts, reqOpts ...RequestOption) (r AttachGatewayResult) {
+	retry = retry.withDefaults()
+	backoff := retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		if attempt >= retry.MaxAttempts {
+			return AttachGatewayWithContext(ctx, c, routingtableID, opts, reqOpts...)
+		}
+		attemptOpts := append(reqOpts[:len(reqOpts):len(reqOpts)], withoutConflictLookup())
+		r = AttachGatewayWithContext(ctx, c, routingtableID, opts, attemptOpts...)
+		if _, ok := r.Err.(gophercloud.ErrDefault409); !ok {
+			return r
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			r.Err = ctx.Err()
+			return r
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
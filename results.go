@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -217,6 +218,13 @@ func (r Result) ExtractIntoSlicePtr(to interface{}, label string) error {
 	}
 }
 
+// RequestID returns the value of the X-OpenStack-Request-Id header of the
+// response, or an empty string if the server did not send one. The header is
+// also kept for failed requests, so it can be logged alongside the error.
+func (r Result) RequestID() string {
+	return r.Header.Get("X-Openstack-Request-Id")
+}
+
 // PrettyPrintJSON creates a string containing the full response body as
 // pretty-printed JSON. It's useful for capturing test fixtures and for
 // debugging extraction bugs. If you include its output in an issue related to
@@ -423,6 +431,120 @@ func (jt *JSONRFC3339ZNoTNoZ) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// nhnCloudLocation is the location NHN Cloud timestamps without an explicit
+// offset are reported in. A fixed UTC+9 zone is used if the zoneinfo database
+// is unavailable; Korea observes no daylight saving time, so both yield the
+// same instants.
+var nhnCloudLocation = func() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Seoul"); err == nil {
+		return loc
+	}
+	return time.FixedZone("KST", 9*60*60)
+}()
+
+// nhnCloudTimeFormats lists the timestamp layouts used by the NHN Cloud API.
+// Only the layouts without a zone are read as KST; the others carry their
+// own offset, or a literal Z for UTC.
+var nhnCloudTimeFormats = []struct {
+	layout string
+	zoned  bool
+}{
+	{"2006-01-02 15:04:05", false},        // Most common format
+	{"2006-01-02T15:04:05", false},        // Alternative format without timezone
+	{"2006-01-02T15:04:05Z", true},        // UTC format
+	{"2006-01-02T15:04:05Z07:00", true},   // Full RFC3339
+	{"2006-01-02 15:04:05.000000", false}, // With microseconds
+	{"2006-01-02T15:04:05.000000Z", true}, // RFC3339 with microseconds
+}
+
+// nhnCloudTimeLayout and nhnCloudTimeLayoutMicro are the layouts NHNCloudTime
+// is marshaled with, depending on its precision.
+const (
+	nhnCloudTimeLayout      = "2006-01-02 15:04:05"
+	nhnCloudTimeLayoutMicro = "2006-01-02 15:04:05.000000"
+)
+
+// NHNCloudTime handles the timestamps returned by the NHN Cloud API, e.g.
+// "2024-02-13 10:45:57" instead of standard RFC3339. Timestamps without an
+// explicit offset are interpreted as KST (Asia/Seoul).
+//
+// A timestamp parsed with fractional seconds, or holding a non-zero
+// sub-second part, is marshaled with microseconds so it round-trips without
+// loss; other timestamps are marshaled to the second.
+type NHNCloudTime struct {
+	time.Time
+
+	// subSecond records that the parsed value carried fractional seconds.
+	subSecond bool
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for NHN Cloud timestamps.
+// Besides the string formats, it accepts a JSON null, which yields the zero
+// time, and an integer, which is read as seconds since the Unix epoch.
+func (ct *NHNCloudTime) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(data))
+	if raw == "null" {
+		*ct = NHNCloudTime{}
+		return nil
+	}
+	if !strings.HasPrefix(raw, `"`) {
+		seconds, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse time %s as Unix seconds: %v", raw, err)
+		}
+		*ct = NHNCloudTime{Time: time.Unix(seconds, 0)}
+		return nil
+	}
+
+	// Remove quotes from JSON string
+	s := strings.Trim(raw, `"`)
+
+	// Handle empty/null values
+	if s == "null" || s == "" {
+		*ct = NHNCloudTime{}
+		return nil
+	}
+
+	var parseErr error
+	for _, format := range nhnCloudTimeFormats {
+		loc := nhnCloudLocation
+		if format.zoned {
+			loc = time.UTC
+		}
+		t, err := time.ParseInLocation(format.layout, s, loc)
+		if err == nil {
+			ct.Time = t
+			ct.subSecond = strings.Contains(s, ".")
+			return nil
+		}
+		parseErr = err
+	}
+
+	return fmt.Errorf("unable to parse time %q with any known format: %v", s, parseErr)
+}
+
+// MarshalJSON implements custom JSON marshaling for NHN Cloud timestamps.
+func (ct NHNCloudTime) MarshalJSON() ([]byte, error) {
+	if ct.Time.IsZero() {
+		return []byte("null"), nil
+	}
+	// Format to match NHN Cloud API format
+	return json.Marshal(ct.String())
+}
+
+// String returns the time in the NHN Cloud API format, in KST, with
+// microseconds if the value has sub-second precision.
+func (ct NHNCloudTime) String() string {
+	t := ct.Time
+	if !t.IsZero() {
+		t = t.In(nhnCloudLocation)
+	}
+	if ct.subSecond || t.Nanosecond() != 0 {
+		return t.Format(nhnCloudTimeLayoutMicro)
+	}
+	return t.Format(nhnCloudTimeLayout)
+}
+
 /*
 Link is an internal type to be used in packages of collection resources that are
 paginated in a certain way.
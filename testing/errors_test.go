@@ -23,3 +23,47 @@ func TestGetResponseCode(t *testing.T) {
 	th.AssertEquals(t, true, ok)
 	th.AssertEquals(t, err.GetStatusCode(), 404)
 }
+
+func TestNeutronError(t *testing.T) {
+	respErr := gophercloud.ErrUnexpectedResponseCode{
+		Actual: 400,
+		Body:   []byte(`{"NeutronError": {"message": "Invalid input for cidr.", "type": "HTTPBadRequest", "detail": ""}}`),
+	}
+
+	var err error = gophercloud.ErrDefault400{ErrUnexpectedResponseCode: respErr}
+
+	errType, message, ok := err.(gophercloud.ErrDefault400).NeutronError()
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, "HTTPBadRequest", errType)
+	th.AssertEquals(t, "Invalid input for cidr.", message)
+
+	respErr.Body = []byte(`{"badRequest": {"message": "Invalid input"}}`)
+	_, _, ok = respErr.NeutronError()
+	th.AssertEquals(t, false, ok)
+
+	respErr.Body = []byte(`Bad Request`)
+	_, _, ok = respErr.NeutronError()
+	th.AssertEquals(t, false, ok)
+}
+
+func TestBodyAsMap(t *testing.T) {
+	respErr := gophercloud.ErrUnexpectedResponseCode{
+		Actual: 409,
+		Body:   []byte(`{"NeutronError": {"message": "Quota exceeded.", "type": "OverQuota"}, "request_id": "req-1"}`),
+	}
+
+	var err error = gophercloud.ErrDefault409{ErrUnexpectedResponseCode: respErr}
+
+	m, merr := err.(gophercloud.ErrDefault409).BodyAsMap()
+	th.AssertNoErr(t, merr)
+	th.AssertEquals(t, "req-1", m["request_id"])
+	th.AssertDeepEquals(t, map[string]interface{}{"message": "Quota exceeded.", "type": "OverQuota"}, m["NeutronError"])
+
+	for _, body := range []string{"Conflict", "", `["a"]`} {
+		respErr.Body = []byte(body)
+		_, merr = respErr.BodyAsMap()
+		if merr == nil {
+			t.Errorf("expected an error for body %q", body)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package testing
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/cloud-barista/nhncloud-sdk-go"
 	th "github.com/cloud-barista/nhncloud-sdk-go/testhelper"
@@ -206,3 +207,53 @@ func TestUnmarshalSliceOfNamedStructs(t *testing.T) {
 	th.AssertEquals(t, "", actual[1].TestPerson.Name)
 	th.AssertEquals(t, "", actual[1].TestPersonExt.Location)
 }
+
+func TestNHNCloudTimeRoundTrip(t *testing.T) {
+	for _, input := range []string{
+		`"2024-02-13 10:45:57"`,
+		`"2024-02-13 10:45:57.123456"`,
+		`"2024-02-13 10:45:57.000000"`,
+	} {
+		var ct gophercloud.NHNCloudTime
+		th.AssertNoErr(t, json.Unmarshal([]byte(input), &ct))
+
+		b, err := json.Marshal(ct)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, input, string(b))
+	}
+}
+
+func TestNHNCloudTimeNullEmptyAndEpoch(t *testing.T) {
+	var s struct {
+		Time gophercloud.NHNCloudTime `json:"time"`
+	}
+
+	s.Time.Time = time.Now()
+	th.AssertNoErr(t, json.Unmarshal([]byte(`{"time": null}`), &s))
+	th.AssertEquals(t, true, s.Time.IsZero())
+
+	s.Time.Time = time.Now()
+	th.AssertNoErr(t, json.Unmarshal([]byte(`{"time": ""}`), &s))
+	th.AssertEquals(t, true, s.Time.IsZero())
+
+	th.AssertNoErr(t, json.Unmarshal([]byte(`{"time": 1707788757}`), &s))
+	th.AssertEquals(t, true, s.Time.Equal(time.Date(2024, 2, 13, 1, 45, 57, 0, time.UTC)))
+	th.AssertEquals(t, "2024-02-13 10:45:57", s.Time.String())
+
+	th.AssertErr(t, json.Unmarshal([]byte(`{"time": 1707788757.5}`), &s))
+}
+
+func TestNHNCloudTimeUTCSuffix(t *testing.T) {
+	// A literal Z marks UTC; only zone-less timestamps are read as KST.
+	for input, expected := range map[string]time.Time{
+		`"2024-02-13T01:45:57Z"`:        time.Date(2024, 2, 13, 1, 45, 57, 0, time.UTC),
+		`"2024-02-13T01:45:57.123456Z"`: time.Date(2024, 2, 13, 1, 45, 57, 123456000, time.UTC),
+		`"2024-02-13T10:45:57"`:         time.Date(2024, 2, 13, 1, 45, 57, 0, time.UTC),
+	} {
+		var ct gophercloud.NHNCloudTime
+		th.AssertNoErr(t, json.Unmarshal([]byte(input), &ct))
+		if !ct.Equal(expected) {
+			t.Errorf("%s: expected %s, got %s", input, expected, ct.UTC())
+		}
+	}
+}